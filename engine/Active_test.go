@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+type enableCounter struct {
+	BaseComponent
+	updates, enables, disables int
+}
+
+func (c *enableCounter) Update() {
+	c.updates++
+}
+
+func (c *enableCounter) OnEnable() {
+	c.enables++
+}
+
+func (c *enableCounter) OnDisable() {
+	c.disables++
+}
+
+type activeScene struct {
+	*SceneData
+	parent, child *GameObject
+	counter       *enableCounter
+}
+
+func (s *activeScene) New() Scene {
+	return &activeScene{SceneData: NewScene("ActiveScene")}
+}
+
+func (s *activeScene) Load() {
+	s.parent = NewGameObject("Parent")
+	s.child = NewGameObject("Child")
+	s.child.Transform().SetParent2(s.parent)
+	s.counter = &enableCounter{BaseComponent: NewComponent()}
+	s.child.AddComponent(s.counter)
+	s.child.AddComponent(NewPhysics(false, 1, 1))
+	s.AddGameObject(s.parent)
+}
+
+func TestActiveInHierarchy(t *testing.T) {
+	h := NewHarness(&activeScene{}, time.Second/60)
+	s := h.Scene().(*activeScene)
+	c := s.counter
+	h.Step(1)
+	if c.updates != 1 || !s.child.Physics.inSpace {
+		t.Fatalf("updates %d, body in space %v", c.updates, s.child.Physics.inSpace)
+	}
+
+	s.parent.SetActive(false)
+	if s.child.ActiveInHierarchy() || !s.child.ActiveSelf() {
+		t.Error("child should be inactive in the hierarchy but active itself")
+	}
+	if c.disables != 1 || s.child.Physics.inSpace {
+		t.Errorf("OnDisable called %d times, body in space %v", c.disables, s.child.Physics.inSpace)
+	}
+	h.Step(1)
+	if c.updates != 1 {
+		t.Error("inactive children should not be updated")
+	}
+
+	s.parent.SetActive(true)
+	if c.enables != 1 || !s.child.Physics.inSpace {
+		t.Errorf("OnEnable called %d times, body in space %v", c.enables, s.child.Physics.inSpace)
+	}
+
+	c.SetEnabled(false)
+	h.Step(1)
+	if c.updates != 1 || c.disables != 2 {
+		t.Errorf("disabled component updated %d times, OnDisable called %d times", c.updates, c.disables)
+	}
+	c.SetEnabled(true)
+	h.Step(1)
+	if c.updates != 2 || c.enables != 2 {
+		t.Errorf("enabled component updated %d times, OnEnable called %d times", c.updates, c.enables)
+	}
+	h.Close()
+}
@@ -5,8 +5,8 @@ import (
 	"github.com/vova616/gl"
 	"image"
 	"image/draw"
-	"log"
-	"os"
+	"io/fs"
+	"path"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -55,12 +55,11 @@ func AnimatedGroupUVs(a Atlas, groups ...ID) (AnimatedUV, map[ID][2]int) {
 }
 
 func AtlasLoadDirectory(path string) (*ManagedAtlas, error) {
-	d, e := os.Open(path)
-	if e != nil {
-		return nil, e
-	}
-	defer d.Close()
-	ds, e := d.Stat()
+	return AtlasLoadDirectoryFS(Files, path)
+}
+
+func AtlasLoadDirectoryFS(fsys fs.FS, path string) (*ManagedAtlas, error) {
+	ds, e := fs.Stat(fsys, path)
 	if e != nil {
 		return nil, e
 	}
@@ -71,7 +70,7 @@ func AtlasLoadDirectory(path string) (*ManagedAtlas, error) {
 
 	atlas := NewManagedAtlas(1024, 512)
 
-	return atlas, atlas.LoadGroup(path)
+	return atlas, atlas.LoadGroupFS(fsys, path)
 }
 
 func IndexUV(a Atlas, id ID) UV {
@@ -122,6 +121,8 @@ type ManagedAtlas struct {
 	groups map[ID][]ID
 	images map[ID]image.Image
 	Tree   *AtlasNode
+	//sources are the files of the images, they are watched when hot reload is enabled.
+	sources map[ID]string
 }
 
 type AtlasNode struct {
@@ -136,12 +137,12 @@ func NewAtlasNode(width, height int) *AtlasNode {
 
 func NewManagedAtlas(width, height int) *ManagedAtlas {
 	m := &ManagedAtlas{
-		image:  image.NewRGBA(image.Rect(0, 0, width, height)),
-		uvs:    make(map[ID]image.Rectangle),
-		groups: make(map[ID][]ID),
-		images: make(map[ID]image.Image),
-		Tree:   NewAtlasNode(width, height)}
-	ResourceManager.Add(m)
+		image:   image.NewRGBA(image.Rect(0, 0, width, height)),
+		uvs:     make(map[ID]image.Rectangle),
+		groups:  make(map[ID][]ID),
+		images:  make(map[ID]image.Image),
+		Tree:    NewAtlasNode(width, height),
+		sources: make(map[ID]string)}
 	return m
 }
 
@@ -150,7 +151,7 @@ func NewRGBA(r image.Rectangle) (*image.RGBA, *MemHandle) {
 	w, h := r.Dx(), r.Dy()
 	memHandle := Allocate(4 * w * h)
 	buf := memHandle.Bytes()
-	ResourceManager.Add(memHandle)
+	Assets.Add(memHandle)
 	return &image.RGBA{buf, 4 * w, r}, memHandle
 }
 */
@@ -162,12 +163,7 @@ func AtlasFromSheet(path string, width, height, frames int) (atlas *ManagedAtlas
 	}
 	fName = atlas.nextGroupID(fName)
 
-	file, e := os.Open(path)
-	if e != nil {
-		return nil, nil, e
-	}
-	defer file.Close()
-	ds, e := file.Stat()
+	ds, e := fs.Stat(Files, path)
 	if e != nil {
 		return nil, nil, e
 	}
@@ -175,20 +171,18 @@ func AtlasFromSheet(path string, width, height, frames int) (atlas *ManagedAtlas
 		return nil, nil, errors.New("The path is not a file. " + path)
 	}
 
-	file.Close()
-
 	img, e := LoadImage(path)
 	if e != nil {
 		return nil, nil, e
 	}
 
 	atlas = &ManagedAtlas{
-		image:  image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy())),
-		uvs:    make(map[ID]image.Rectangle),
-		groups: make(map[ID][]ID),
-		images: make(map[ID]image.Image),
-		Tree:   NewAtlasNode(img.Bounds().Dx(), img.Bounds().Dy())}
-	ResourceManager.Add(atlas)
+		image:   image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy())),
+		uvs:     make(map[ID]image.Rectangle),
+		groups:  make(map[ID][]ID),
+		images:  make(map[ID]image.Image),
+		Tree:    NewAtlasNode(img.Bounds().Dx(), img.Bounds().Dy()),
+		sources: make(map[ID]string)}
 
 	draw.Draw(atlas.image, atlas.image.Bounds(), img, image.Point{0, 0}, draw.Src)
 
@@ -213,6 +207,7 @@ func AtlasFromSheet(path string, width, height, frames int) (atlas *ManagedAtlas
 }
 
 func (atlas *ManagedAtlas) Release() {
+	unwatchFiles(atlas)
 	if atlas.Texture != nil {
 		atlas.Texture.Release()
 	}
@@ -226,6 +221,7 @@ func (atlas *ManagedAtlas) Release() {
 	atlas.groups = nil
 	atlas.images = nil
 	atlas.Tree = nil
+	atlas.sources = nil
 }
 
 func (node *AtlasNode) Insert(img image.Image, id ID) *AtlasNode {
@@ -304,12 +300,7 @@ func (atlas *ManagedAtlas) LoadGIFID(path string, groupID ID) (err error) {
 		panic("id already exists.")
 	}
 
-	file, e := os.Open(path)
-	if e != nil {
-		return e
-	}
-	defer file.Close()
-	ds, e := file.Stat()
+	ds, e := fs.Stat(Files, path)
 	if e != nil {
 		return e
 	}
@@ -317,8 +308,6 @@ func (atlas *ManagedAtlas) LoadGIFID(path string, groupID ID) (err error) {
 		return errors.New("The path is not a file. " + path)
 	}
 
-	file.Close()
-
 	imgs, e := LoadGIF(path)
 	if e != nil {
 		return e
@@ -351,12 +340,7 @@ func (atlas *ManagedAtlas) LoadGroupSheetOffset(path string, pt image.Point, wid
 	}
 	fName = atlas.nextGroupID(fName)
 
-	file, e := os.Open(path)
-	if e != nil {
-		return e, nil
-	}
-	defer file.Close()
-	ds, e := file.Stat()
+	ds, e := fs.Stat(Files, path)
 	if e != nil {
 		return e, nil
 	}
@@ -364,8 +348,6 @@ func (atlas *ManagedAtlas) LoadGroupSheetOffset(path string, pt image.Point, wid
 		return errors.New("The path is not a file. " + path), nil
 	}
 
-	file.Close()
-
 	img, e := LoadImage(path)
 	if e != nil {
 		return e, nil
@@ -395,21 +377,23 @@ func (atlas *ManagedAtlas) LoadGroupSheetOffset(path string, pt image.Point, wid
 }
 
 func (atlas *ManagedAtlas) LoadGroup(path string) error {
-	d, e := os.Open(path)
-	if e != nil {
-		return e
-	}
-	defer d.Close()
-	ds, e := d.Stat()
+	return atlas.LoadGroupFS(Files, path)
+}
+
+// LoadGroupFS loads every image in dir, "name.png" with "name_0.png", "name_1.png"... next to it are loaded as the group name.
+func (atlas *ManagedAtlas) LoadGroupFS(fsys fs.FS, dir string) error {
+	ds, e := fs.Stat(fsys, dir)
 	if e != nil {
 		return e
 	}
 	if !ds.IsDir() {
 
-		return errors.New("The path is not a directory. " + path)
+		return errors.New("The path is not a directory. " + dir)
 	}
+	//Only files of Files can be hot reloaded.
+	watch := fsys == fs.FS(Files)
 
-	files, er := d.Readdir(0)
+	files, er := fs.ReadDir(fsys, dir)
 	for _, file := range files {
 		fullName := file.Name()
 		fullName = filepath.Base(fullName)
@@ -424,32 +408,37 @@ func (atlas *ManagedAtlas) LoadGroup(path string) error {
 
 		nIndex := strings.LastIndex(fName, "_")
 		if nIndex == -1 {
-			fload := d.Name() + "/" + fullName
+			fload := path.Join(dir, fullName)
 
-			img, e := LoadImage(fload)
+			img, e := LoadImageFS(fsys, fload)
 			if e != nil {
-				log.Println(e)
+				LogAssets.Error("Atlas image loading failed", "path", fload, "err", e)
 				continue
 			}
 
 			atlas.AddImage(img, fName)
+			if watch {
+				atlas.setSource(fName, fload)
+			}
 			group := make([]ID, 1)
 			group[0] = fName
 
-			fulldir := d.Name() + "/" + fName + "_"
+			fulldir := path.Join(dir, fName+"_")
 			for i := 0; ; i++ {
 				is := strconv.FormatInt(int64(i), 10)
 				fload = fulldir + is + ext
-				f, e := os.Open(fload)
+				_, e := fs.Stat(fsys, fload)
 				if e == nil {
 					//log.Println(fload)
-					f.Close()
-					img, e := LoadImage(fload)
+					img, e := LoadImageFS(fsys, fload)
 					if e != nil {
-						log.Println(e)
+						LogAssets.Error("Atlas image loading failed", "path", fload, "err", e)
 						continue
 					}
 					atlas.AddImage(img, fName+is)
+					if watch {
+						atlas.setSource(fName+is, fload)
+					}
 					group = append(group, fName+is)
 				} else {
 					if i > 1 {
@@ -479,6 +468,7 @@ func (ma *ManagedAtlas) LoadImage(path string) (err error, id ID) {
 
 	id = ma.nextImageID(fName)
 	ma.images[id] = img
+	ma.setSource(id, path)
 
 	return nil, id
 }
@@ -528,10 +518,18 @@ func (ma *ManagedAtlas) LoadImageID(path string, id ID) error {
 	}
 
 	ma.images[id] = img
+	ma.setSource(id, path)
 
 	return nil
 }
 
+func (ma *ManagedAtlas) setSource(id ID, path string) {
+	if ma.sources == nil {
+		ma.sources = make(map[ID]string)
+	}
+	ma.sources[id] = path
+}
+
 func (ma *ManagedAtlas) AddImage(img image.Image, id ID) error {
 	if img == nil {
 		return errors.New("image is nil")
@@ -574,7 +572,18 @@ func (ma *ManagedAtlas) Indexs() []ID {
 	return images
 }
 
+// BuildAtlas packs the loaded images and uploads the atlas texture.
 func (ma *ManagedAtlas) BuildAtlas() error {
+	if err := ma.PrepareAtlas(); err != nil {
+		return err
+	}
+	ma.Upload()
+	return nil
+}
+
+// PrepareAtlas packs the loaded images into the atlas image without touching OpenGL,
+// so it can run in a background scene loader. Upload has to be called after it on the main thread.
+func (ma *ManagedAtlas) PrepareAtlas() error {
 	for {
 		maxArea := 0
 		var bigImage image.Image = nil
@@ -612,8 +621,48 @@ func (ma *ManagedAtlas) BuildAtlas() error {
 		ma.uvs[bigID] = rect
 		ma.images[bigID] = nil
 	}
+	return nil
+}
+
+// Upload creates the atlas texture from the prepared image.
+func (ma *ManagedAtlas) Upload() {
+	if ma.image == nil {
+		return
+	}
 	ma.Texture = NewRGBATexture(ma.image.Pix, ma.image.Bounds().Dx(), ma.image.Bounds().Dy())
 	ma.image.Pix = nil
 	ma.image = nil
+	//The atlas is added when it's uploaded and not when it's made, so an atlas that is packed by a scene loader
+	//belongs to the loading scene and not to the scene that runs.
+	Assets.Add(ma)
+	for id, path := range ma.sources {
+		id := id
+		watchFile(path, ma, func() error { return ma.reloadImage(id) })
+	}
+}
+
+// reloadImage draws the file of the image again into its place in the atlas texture, the UVs don't change.
+// An image that got bigger is clipped to its old size.
+func (ma *ManagedAtlas) reloadImage(id ID) error {
+	rect, exist := ma.uvs[id]
+	if !exist || ma.Texture == nil {
+		return errors.New("image is not in the atlas")
+	}
+	img, err := LoadImage(ma.sources[id])
+	if err != nil {
+		return err
+	}
+	if img.Bounds().Dx() != rect.Dx() || img.Bounds().Dy() != rect.Dy() {
+		LogAssets.Warn("Atlas image changed size, it is clipped", "id", id, "size", img.Bounds().Size(), "atlasSize", rect.Size())
+	}
+
+	pixels := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(pixels, pixels.Bounds(), img, img.Bounds().Min, draw.Src)
+
+	ma.Texture.Bind()
+	gl.TexSubImage2D(ma.Texture.target, 0, rect.Min.X, rect.Min.Y, rect.Dx(), rect.Dy(), gl.RGBA, gl.UNSIGNED_BYTE, pixels.Pix)
+	if ma.Texture.mipmaps {
+		gl.GenerateMipmap(ma.Texture.target)
+	}
 	return nil
 }
@@ -0,0 +1,266 @@
+package engine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	yaml "gopkg.in/yaml.v3"
+	"path"
+	"sort"
+	"strings"
+)
+
+/*
+	Tree files describe a behavior tree with the node types that are registered with RegisterNode.
+	Every node has a type, an optional name for traces, its children ("child" for one) and its parameters:
+
+		{
+			"type": "Sequence",
+			"children": [
+				{"type": "Wait", "min": 0, "max": 5},
+				{"type": "Retry", "child": {"type": "IsPlayerClose", "distance": 600}},
+				{"type": "Attack", "name": "Charge"}
+			]
+		}
+
+	Files that end with .yaml or .yml are YAML, the rest are JSON. The built in types are Sequence, Selector,
+	Parallel (policy "all" or "one"), Inverter, Repeat (count), Retry (attempts), Timeout (seconds),
+	Cooldown (seconds) and Wait (seconds, or min and max).
+*/
+
+// NodeParams are the fields of a node in a tree file other than type, name and children.
+type NodeParams map[string]interface{}
+
+// Float returns the number of key, def if it's missing or isn't a number.
+func (p NodeParams) Float(key string, def float64) float64 {
+	switch v := p[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	}
+	return def
+}
+
+func (p NodeParams) Int(key string, def int) int {
+	return int(p.Float(key, float64(def)))
+}
+
+func (p NodeParams) String(key string, def string) string {
+	if v, ok := p[key].(string); ok {
+		return v
+	}
+	return def
+}
+
+func (p NodeParams) Bool(key string, def bool) bool {
+	if v, ok := p[key].(bool); ok {
+		return v
+	}
+	return def
+}
+
+// NodeFactory creates a node of a tree file from its parameters and its children, which are already built.
+type NodeFactory func(params NodeParams, children []Node) (Node, error)
+
+// NodeDef is a node of a tree file.
+type NodeDef struct {
+	Type     string
+	Name     string
+	Params   NodeParams
+	Children []*NodeDef
+}
+
+var (
+	nodeFactories = make(map[string]NodeFactory)
+	behaviorTrees = make(map[string]Node)
+)
+
+func init() {
+	RegisterNode("Sequence", func(p NodeParams, children []Node) (Node, error) {
+		return NewSequence(children...), nil
+	})
+	RegisterNode("Selector", func(p NodeParams, children []Node) (Node, error) {
+		return NewSelector(children...), nil
+	})
+	RegisterNode("Parallel", func(p NodeParams, children []Node) (Node, error) {
+		switch policy := p.String("policy", "all"); policy {
+		case "all":
+			return NewParallel(RequireAll, children...), nil
+		case "one":
+			return NewParallel(RequireOne, children...), nil
+		default:
+			return nil, fmt.Errorf("unknown parallel policy %q", policy)
+		}
+	})
+	RegisterNode("Inverter", decorator(func(p NodeParams, child Node) Node {
+		return NewInverter(child)
+	}))
+	RegisterNode("Repeat", decorator(func(p NodeParams, child Node) Node {
+		return NewRepeat(p.Int("count", 0), child)
+	}))
+	RegisterNode("Retry", decorator(func(p NodeParams, child Node) Node {
+		return NewRetry(p.Int("attempts", 0), child)
+	}))
+	RegisterNode("Timeout", decorator(func(p NodeParams, child Node) Node {
+		return NewTimeout(p.Float("seconds", 1), child)
+	}))
+	RegisterNode("Cooldown", decorator(func(p NodeParams, child Node) Node {
+		return NewCooldown(p.Float("seconds", 1), child)
+	}))
+	RegisterNode("Wait", func(p NodeParams, children []Node) (Node, error) {
+		seconds := p.Float("seconds", 1)
+		return NewWaitRandom(p.Float("min", seconds), p.Float("max", seconds)), nil
+	})
+}
+
+// decorator makes a factory for a node with exactly one child.
+func decorator(fn func(p NodeParams, child Node) Node) NodeFactory {
+	return func(p NodeParams, children []Node) (Node, error) {
+		if len(children) != 1 {
+			return nil, fmt.Errorf("needs one child, has %d", len(children))
+		}
+		return fn(p, children[0]), nil
+	}
+}
+
+// RegisterNode makes a node type usable in tree files, registering the same name twice replaces the old factory.
+func RegisterNode(name string, factory NodeFactory) {
+	nodeFactories[name] = factory
+}
+
+func RegisteredNodes() []string {
+	names := make([]string, 0, len(nodeFactories))
+	for name := range nodeFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadBehaviorTree loads a tree file, trees are cached by path and shared by every BehaviorTree that uses them.
+func LoadBehaviorTree(path string) (Node, error) {
+	if root, exists := behaviorTrees[path]; exists {
+		return root, nil
+	}
+	data, err := Files.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	def, err := DecodeNodeDef(data, treeFormat(path))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	root, err := BuildBehaviorTree(def)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	behaviorTrees[path] = root
+	return root, nil
+}
+
+func treeFormat(name string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+		return "yaml"
+	}
+	return "json"
+}
+
+// DecodeNodeDef parses a tree file, format is "json" or "yaml".
+func DecodeNodeDef(data []byte, format string) (*NodeDef, error) {
+	var v interface{}
+	var err error
+	switch format {
+	case "json":
+		err = json.Unmarshal(data, &v)
+	case "yaml":
+		err = yaml.Unmarshal(data, &v)
+	default:
+		return nil, fmt.Errorf("unknown tree format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseNodeDef(v, "root")
+}
+
+func parseNodeDef(v interface{}, where string) (*NodeDef, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s: a node must be an object", where)
+	}
+	def := &NodeDef{Params: make(NodeParams)}
+	for key, value := range m {
+		switch key {
+		case "type":
+			def.Type, _ = value.(string)
+		case "name":
+			def.Name, _ = value.(string)
+		case "child", "children":
+		default:
+			def.Params[key] = value
+		}
+	}
+	if def.Type == "" {
+		return nil, fmt.Errorf("%s: the node has no type", where)
+	}
+	where += "/" + def.Type
+
+	children, hasChildren := m["children"]
+	child, hasChild := m["child"]
+	if hasChildren && hasChild {
+		return nil, errors.New(where + ": a node has a child or children, not both")
+	}
+	if hasChild {
+		children = []interface{}{child}
+	}
+	if children != nil {
+		list, ok := children.([]interface{})
+		if !ok {
+			return nil, errors.New(where + ": children must be a list")
+		}
+		for i, c := range list {
+			cdef, err := parseNodeDef(c, fmt.Sprintf("%s[%d]", where, i))
+			if err != nil {
+				return nil, err
+			}
+			def.Children = append(def.Children, cdef)
+		}
+	}
+	return def, nil
+}
+
+// BuildBehaviorTree creates the nodes of a tree file, every node is named by its name or its type.
+func BuildBehaviorTree(def *NodeDef) (Node, error) {
+	return buildNode(def, "root/"+def.Type)
+}
+
+func buildNode(def *NodeDef, where string) (Node, error) {
+	factory, exists := nodeFactories[def.Type]
+	if !exists {
+		return nil, fmt.Errorf("%s: unknown node type %q", where, def.Type)
+	}
+	children := make([]Node, 0, len(def.Children))
+	for i, c := range def.Children {
+		n, err := buildNode(c, fmt.Sprintf("%s[%d]/%s", where, i, c.Type))
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, n)
+	}
+	n, err := factory(def.Params, children)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", where, err)
+	}
+	name := def.Name
+	if name == "" {
+		name = def.Type
+	}
+	SetNodeName(n, name)
+	return n, nil
+}
@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestBehaviorTreeFiles(t *testing.T) {
+	hits := 0
+	RegisterNode("TestHit", func(p NodeParams, children []Node) (Node, error) {
+		damage := p.Int("damage", 1)
+		return NewAction(func(bt *BehaviorTree) Status {
+			hits += damage
+			return StatusSuccess
+		}), nil
+	})
+
+	jsonDef, err := DecodeNodeDef([]byte(`{
+		"type": "Sequence",
+		"children": [
+			{"type": "TestHit", "name": "Punch", "damage": 2},
+			{"type": "Inverter", "child": {"type": "TestHit"}}
+		]
+	}`), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	yamlDef, err := DecodeNodeDef([]byte("type: Sequence\nchildren:\n  - type: TestHit\n    name: Punch\n    damage: 2\n  - type: Inverter\n    child:\n      type: TestHit\n"), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, def := range []*NodeDef{jsonDef, yamlDef} {
+		root, err := BuildBehaviorTree(def)
+		if err != nil {
+			t.Fatal(err)
+		}
+		hits = 0
+		bt := NewBehaviorTree(root)
+		bt.StartTrace(1)
+		if s := bt.Tick(); s != StatusFailure || hits != 3 {
+			t.Errorf("tick returned %v with %d hits, expected Failure with 3", s, hits)
+		}
+		bt.Tick()
+		if len(bt.Trace()) != 4 {
+			t.Errorf("trace has %d entries, expected the 4 nodes of the last tick", len(bt.Trace()))
+		}
+		out := new(bytes.Buffer)
+		bt.DumpTrace(out)
+		expected := "tick 2\n  Sequence Failure\n    Punch Success\n    Inverter Failure\n      TestHit Success\n"
+		if out.String() != expected {
+			t.Errorf("trace dump is\n%s\nexpected\n%s", out, expected)
+		}
+	}
+
+	def, err := DecodeNodeDef([]byte(`{"type": "Selector", "children": [{"type": "Repeat", "child": {"type": "Nope"}}]}`), "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := BuildBehaviorTree(def); err == nil || !strings.Contains(err.Error(), "root/Selector[0]/Repeat[0]/Nope") {
+		t.Errorf("expected an error with the path of the unknown node, got %v", err)
+	}
+	if _, err := DecodeNodeDef([]byte(`{"children": []}`), "json"); err == nil {
+		t.Error("a node without a type was accepted")
+	}
+}
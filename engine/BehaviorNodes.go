@@ -0,0 +1,356 @@
+package engine
+
+import (
+	"math/rand"
+	"time"
+)
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// SequenceNode ticks its children in order until one fails, it succeeds when all of them succeed.
+type SequenceNode struct {
+	Nodes []Node
+}
+
+func NewSequence(children ...Node) *SequenceNode {
+	return &SequenceNode{children}
+}
+
+func (n *SequenceNode) Children() []Node {
+	return n.Nodes
+}
+
+func (n *SequenceNode) Tick(bt *BehaviorTree) Status {
+	s := bt.state(n)
+	for s.index < len(n.Nodes) {
+		switch bt.TickNode(n.Nodes[s.index]) {
+		case StatusRunning:
+			return StatusRunning
+		case StatusFailure:
+			s.reset()
+			return StatusFailure
+		}
+		s.index++
+	}
+	s.reset()
+	return StatusSuccess
+}
+
+// SelectorNode ticks its children in order until one succeeds, it fails when all of them fail.
+type SelectorNode struct {
+	Nodes []Node
+}
+
+func NewSelector(children ...Node) *SelectorNode {
+	return &SelectorNode{children}
+}
+
+func (n *SelectorNode) Children() []Node {
+	return n.Nodes
+}
+
+func (n *SelectorNode) Tick(bt *BehaviorTree) Status {
+	s := bt.state(n)
+	for s.index < len(n.Nodes) {
+		switch bt.TickNode(n.Nodes[s.index]) {
+		case StatusRunning:
+			return StatusRunning
+		case StatusSuccess:
+			s.reset()
+			return StatusSuccess
+		}
+		s.index++
+	}
+	s.reset()
+	return StatusFailure
+}
+
+type ParallelPolicy byte
+
+const (
+	//RequireAll succeeds when every child succeeds and fails when one fails.
+	RequireAll = ParallelPolicy(iota)
+	//RequireOne succeeds when one child succeeds and fails when every child fails.
+	RequireOne
+)
+
+// ParallelNode ticks all of its children every tick, the children that are still running are aborted when it's done.
+type ParallelNode struct {
+	Policy ParallelPolicy
+	Nodes  []Node
+}
+
+func NewParallel(policy ParallelPolicy, children ...Node) *ParallelNode {
+	return &ParallelNode{policy, children}
+}
+
+func (n *ParallelNode) Children() []Node {
+	return n.Nodes
+}
+
+func (n *ParallelNode) Tick(bt *BehaviorTree) Status {
+	s := bt.state(n)
+	if len(s.statuses) != len(n.Nodes) {
+		s.statuses = append(s.statuses[:0], make([]Status, len(n.Nodes))...)
+	}
+	successes, failures := 0, 0
+	for i, c := range n.Nodes {
+		if s.statuses[i] != StatusSuccess && s.statuses[i] != StatusFailure {
+			s.statuses[i] = bt.TickNode(c)
+		}
+		switch s.statuses[i] {
+		case StatusSuccess:
+			successes++
+		case StatusFailure:
+			failures++
+		}
+	}
+
+	result := StatusRunning
+	switch n.Policy {
+	case RequireAll:
+		if failures > 0 {
+			result = StatusFailure
+		} else if successes == len(n.Nodes) {
+			result = StatusSuccess
+		}
+	case RequireOne:
+		if successes > 0 {
+			result = StatusSuccess
+		} else if failures == len(n.Nodes) {
+			result = StatusFailure
+		}
+	}
+	if result != StatusRunning {
+		for i, c := range n.Nodes {
+			if s.statuses[i] == StatusRunning {
+				bt.Abort(c)
+			}
+		}
+		s.reset()
+	}
+	return result
+}
+
+// InverterNode turns the Success of its child into Failure and the other way around.
+type InverterNode struct {
+	Child Node
+}
+
+func NewInverter(child Node) *InverterNode {
+	return &InverterNode{child}
+}
+
+func (n *InverterNode) Children() []Node {
+	return []Node{n.Child}
+}
+
+func (n *InverterNode) Tick(bt *BehaviorTree) Status {
+	switch bt.TickNode(n.Child) {
+	case StatusSuccess:
+		return StatusFailure
+	case StatusFailure:
+		return StatusSuccess
+	}
+	return StatusRunning
+}
+
+// RepeatNode runs its child again every time it succeeds, Count times or forever if Count is 0.
+// It fails when the child fails, one run of the child starts in every tick.
+type RepeatNode struct {
+	Count int
+	Child Node
+}
+
+func NewRepeat(count int, child Node) *RepeatNode {
+	return &RepeatNode{count, child}
+}
+
+func (n *RepeatNode) Children() []Node {
+	return []Node{n.Child}
+}
+
+func (n *RepeatNode) Tick(bt *BehaviorTree) Status {
+	s := bt.state(n)
+	switch bt.TickNode(n.Child) {
+	case StatusFailure:
+		s.reset()
+		return StatusFailure
+	case StatusSuccess:
+		s.count++
+		if n.Count > 0 && s.count >= n.Count {
+			s.reset()
+			return StatusSuccess
+		}
+	}
+	return StatusRunning
+}
+
+// RetryNode runs its child again every time it fails, it fails after Attempts failures (never if Attempts is 0).
+type RetryNode struct {
+	Attempts int
+	Child    Node
+}
+
+func NewRetry(attempts int, child Node) *RetryNode {
+	return &RetryNode{attempts, child}
+}
+
+func (n *RetryNode) Children() []Node {
+	return []Node{n.Child}
+}
+
+func (n *RetryNode) Tick(bt *BehaviorTree) Status {
+	s := bt.state(n)
+	switch bt.TickNode(n.Child) {
+	case StatusSuccess:
+		s.reset()
+		return StatusSuccess
+	case StatusFailure:
+		s.count++
+		if n.Attempts > 0 && s.count >= n.Attempts {
+			s.reset()
+			return StatusFailure
+		}
+	}
+	return StatusRunning
+}
+
+// TimeoutNode aborts its child and fails if the child runs longer than Seconds of game time.
+type TimeoutNode struct {
+	Seconds float64
+	Child   Node
+}
+
+func NewTimeout(seconds float64, child Node) *TimeoutNode {
+	return &TimeoutNode{seconds, child}
+}
+
+func (n *TimeoutNode) Children() []Node {
+	return []Node{n.Child}
+}
+
+func (n *TimeoutNode) Tick(bt *BehaviorTree) Status {
+	s := bt.state(n)
+	if !s.running {
+		s.running = true
+		s.start = clock.Time()
+	}
+	status := bt.TickNode(n.Child)
+	if status == StatusRunning && clock.Time()-s.start >= seconds(n.Seconds) {
+		bt.Abort(n.Child)
+		status = StatusFailure
+	}
+	if status != StatusRunning {
+		s.reset()
+	}
+	return status
+}
+
+// CooldownNode fails without ticking its child for Seconds after the child is done.
+type CooldownNode struct {
+	Seconds float64
+	Child   Node
+}
+
+func NewCooldown(seconds float64, child Node) *CooldownNode {
+	return &CooldownNode{seconds, child}
+}
+
+func (n *CooldownNode) Children() []Node {
+	return []Node{n.Child}
+}
+
+func (n *CooldownNode) Tick(bt *BehaviorTree) Status {
+	s := bt.state(n)
+	if clock.Time() < s.until {
+		return StatusFailure
+	}
+	status := bt.TickNode(n.Child)
+	if status != StatusRunning {
+		s.until = clock.Time() + seconds(n.Seconds)
+	}
+	return status
+}
+
+// ConditionNode succeeds if Cond returns true and fails if not. With a child it's a guard,
+// the child runs while Cond returns true and is aborted when it returns false.
+type ConditionNode struct {
+	Cond  func(bt *BehaviorTree) bool
+	Child Node
+}
+
+// NewCondition returns a condition, child can be nil.
+func NewCondition(cond func(bt *BehaviorTree) bool, child Node) *ConditionNode {
+	return &ConditionNode{cond, child}
+}
+
+func (n *ConditionNode) Children() []Node {
+	if n.Child == nil {
+		return nil
+	}
+	return []Node{n.Child}
+}
+
+func (n *ConditionNode) Tick(bt *BehaviorTree) Status {
+	if !n.Cond(bt) {
+		if n.Child != nil {
+			bt.Abort(n.Child)
+		}
+		return StatusFailure
+	}
+	if n.Child == nil {
+		return StatusSuccess
+	}
+	return bt.TickNode(n.Child)
+}
+
+// ActionNode is a leaf that calls Fn, the game logic of a tree.
+type ActionNode struct {
+	Fn func(bt *BehaviorTree) Status
+}
+
+func NewAction(fn func(bt *BehaviorTree) Status) *ActionNode {
+	return &ActionNode{fn}
+}
+
+func (n *ActionNode) Children() []Node {
+	return nil
+}
+
+func (n *ActionNode) Tick(bt *BehaviorTree) Status {
+	return n.Fn(bt)
+}
+
+// WaitNode runs for a random time between Min and Max seconds of game time and succeeds.
+type WaitNode struct {
+	Min, Max float64
+}
+
+func NewWait(seconds float64) *WaitNode {
+	return &WaitNode{seconds, seconds}
+}
+
+func NewWaitRandom(min, max float64) *WaitNode {
+	return &WaitNode{min, max}
+}
+
+func (n *WaitNode) Children() []Node {
+	return nil
+}
+
+func (n *WaitNode) Tick(bt *BehaviorTree) Status {
+	s := bt.state(n)
+	if !s.running {
+		s.running = true
+		s.start = clock.Time()
+		s.wait = seconds(n.Min + rand.Float64()*(n.Max-n.Min))
+	}
+	if clock.Time()-s.start >= s.wait {
+		s.reset()
+		return StatusSuccess
+	}
+	return StatusRunning
+}
@@ -1,22 +1,353 @@
 package engine
 
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
 /*
-TODO:
-WRITE IT LOL
+	A behavior tree is built from nodes and runs as a component of its game object:
 
+		tree := engine.NewBehaviorTree(engine.NewSelector(
+			engine.NewSequence(
+				engine.NewCondition(isPlayerClose, nil),
+				engine.NewCooldown(2, engine.NewAction(attack)),
+			),
+			engine.NewSequence(engine.NewWait(1), engine.NewAction(wander)),
+		))
+		enemy.AddComponent(tree)
 
+	The tree is ticked in Update, so it stops when its game object is destroyed or deactivated and
+	the nodes never see a destroyed game object. Nodes don't keep state, the BehaviorTree keeps the state of
+	every node it runs, so trees can share nodes and cloning the game object clones the tree.
+*/
 
+// Status is the result of ticking a node.
+type Status byte
 
+const (
+	StatusSuccess = Status(iota + 1)
+	StatusFailure
+	//StatusRunning means the node isn't done, it is ticked again in the next tick of the tree.
+	StatusRunning
+)
 
-*/
+func (s Status) String() string {
+	switch s {
+	case StatusSuccess:
+		return "Success"
+	case StatusFailure:
+		return "Failure"
+	case StatusRunning:
+		return "Running"
+	}
+	return "None"
+}
 
-import (
-	//"log"
-	"math/rand"
-	"time"
+// Node is a node of a behavior tree. Tick runs the node once, a node that has children ticks them with bt.TickNode.
+type Node interface {
+	Tick(bt *BehaviorTree) Status
+	Children() []Node
+}
+
+// nodeState is what a node remembers between ticks, it is cleared when the node is done or aborted.
+type nodeState struct {
+	running  bool
+	index    int
+	count    int
+	start    time.Duration
+	wait     time.Duration
+	statuses []Status
+
+	//until is the end of a cooldown, it stays when the node is aborted.
+	until time.Duration
+}
+
+func (s *nodeState) reset() {
+	s.running = false
+	s.index = 0
+	s.count = 0
+	s.start = 0
+	s.wait = 0
+	s.statuses = s.statuses[:0]
+}
+
+type BehaviorTree struct {
+	BaseComponent
+	Root       Node
+	Blackboard *Blackboard
+	//Path is a tree file (see LoadBehaviorTree) that is loaded on Start if Root is nil.
+	Path string
+
+	status Status
+	states map[Node]*nodeState
+	ticks  int
+
+	traceTicks int
+	trace      []TraceEntry
+	depth      int
+}
+
+// TraceEntry is one node that ran in a traced tick, entries are in the order the nodes started.
+type TraceEntry struct {
+	Tick   int
+	Depth  int
+	Node   string
+	Status Status
+}
+
+func NewBehaviorTree(root Node) *BehaviorTree {
+	return &BehaviorTree{BaseComponent: NewComponent(), Root: root, Blackboard: NewBlackboard()}
+}
+
+func (bt *BehaviorTree) Start() {
+	if bt.Root == nil && bt.Path != "" {
+		root, err := LoadBehaviorTree(bt.Path)
+		if err != nil {
+			LogEngine.Error("Behavior tree loading failed", "path", bt.Path, "err", err)
+			return
+		}
+		bt.Root = root
+	}
+}
+
+func (bt *BehaviorTree) Update() {
+	bt.Tick()
+}
+
+// Tick runs the tree once, a tree that returned Success or Failure starts again from the root.
+func (bt *BehaviorTree) Tick() Status {
+	bt.ticks++
+	if bt.traceTicks > 0 {
+		bt.trimTrace()
+	}
+	if bt.Root == nil {
+		bt.status = StatusFailure
+		return bt.status
+	}
+	bt.status = bt.TickNode(bt.Root)
+	return bt.status
+}
+
+// TickNode ticks a child node, custom composites and decorators call it for their children.
+func (bt *BehaviorTree) TickNode(n Node) Status {
+	if bt.traceTicks == 0 {
+		return n.Tick(bt)
+	}
+	i := len(bt.trace)
+	bt.trace = append(bt.trace, TraceEntry{Tick: bt.ticks, Depth: bt.depth, Node: NodeName(n)})
+	bt.depth++
+	status := n.Tick(bt)
+	bt.depth--
+	bt.trace[i].Status = status
+	return status
+}
+
+// StartTrace records the nodes that run and what they return, the entries of the last ticks ticks are kept.
+func (bt *BehaviorTree) StartTrace(ticks int) {
+	if ticks < 1 {
+		ticks = 1
+	}
+	bt.traceTicks = ticks
+	bt.trace = bt.trace[:0]
+}
+
+// StopTrace stops recording, the recorded entries stay until the next StartTrace.
+func (bt *BehaviorTree) StopTrace() {
+	bt.traceTicks = 0
+}
+
+func (bt *BehaviorTree) Trace() []TraceEntry {
+	return bt.trace
+}
+
+func (bt *BehaviorTree) trimTrace() {
+	oldest := bt.ticks - bt.traceTicks
+	i := 0
+	for i < len(bt.trace) && bt.trace[i].Tick <= oldest {
+		i++
+	}
+	if i > 0 {
+		bt.trace = append(bt.trace[:0], bt.trace[i:]...)
+	}
+}
+
+// DumpTrace writes the recorded ticks, a line for every node indented by its depth:
+//
+//	tick 12
+//	  Sequence Running
+//	    IsPlayerClose Running
+func (bt *BehaviorTree) DumpTrace(w io.Writer) error {
+	tick := 0
+	for _, e := range bt.trace {
+		if e.Tick != tick {
+			tick = e.Tick
+			if _, err := fmt.Fprintf(w, "tick %d\n", tick); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s%s %v\n", strings.Repeat("  ", e.Depth+1), e.Node, e.Status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var (
+	nodeNamesLock sync.Mutex
+	nodeNames     = make(map[Node]string)
 )
 
+// SetNodeName names n in traces and dumps, tree files name every node they create.
+func SetNodeName(n Node, name string) {
+	nodeNamesLock.Lock()
+	nodeNames[n] = name
+	nodeNamesLock.Unlock()
+}
+
+// NodeName returns the name set with SetNodeName, or the type of the node without "Node" (SequenceNode is Sequence).
+func NodeName(n Node) string {
+	nodeNamesLock.Lock()
+	name, exists := nodeNames[n]
+	nodeNamesLock.Unlock()
+	if exists {
+		return name
+	}
+	typ := reflect.TypeOf(n)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return strings.TrimSuffix(typ.Name(), "Node")
+}
+
+// DumpBehaviorTree writes the nodes of a tree, one line for every node indented by its depth.
+func DumpBehaviorTree(w io.Writer, root Node) error {
+	return dumpNode(w, root, 0)
+}
+
+func dumpNode(w io.Writer, n Node, depth int) error {
+	if _, err := fmt.Fprintf(w, "%s%s\n", strings.Repeat("  ", depth), NodeName(n)); err != nil {
+		return err
+	}
+	for _, c := range n.Children() {
+		if err := dumpNode(w, c, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status returns the result of the last tick.
+func (bt *BehaviorTree) Status() Status {
+	return bt.status
+}
+
+// Abort clears the state of n and its children, a running node starts over the next time it's ticked.
+func (bt *BehaviorTree) Abort(n Node) {
+	if s, exists := bt.states[n]; exists {
+		s.reset()
+	}
+	for _, c := range n.Children() {
+		bt.Abort(c)
+	}
+}
+
+// Reset aborts the whole tree, the next tick starts from the root.
+func (bt *BehaviorTree) Reset() {
+	if bt.Root != nil {
+		bt.Abort(bt.Root)
+	}
+	bt.status = 0
+}
+
+// OnDisable resets the tree, a disabled or deactivated tree starts over when it's enabled again.
+func (bt *BehaviorTree) OnDisable() {
+	bt.Reset()
+}
+
+// Clone gives the copy its own blackboard and node states, the nodes are shared.
+func (bt *BehaviorTree) Clone() {
+	bt.Blackboard = bt.Blackboard.Clone()
+	bt.states = nil
+	bt.status = 0
+	bt.ticks = 0
+	bt.traceTicks = 0
+	bt.trace = nil
+	bt.depth = 0
+}
+
+func (bt *BehaviorTree) state(n Node) *nodeState {
+	if bt.states == nil {
+		bt.states = make(map[Node]*nodeState)
+	}
+	s, exists := bt.states[n]
+	if !exists {
+		s = &nodeState{}
+		bt.states[n] = s
+	}
+	return s
+}
+
+// Blackboard holds the values the nodes of a tree share, every BehaviorTree has its own.
+type Blackboard struct {
+	values map[string]interface{}
+}
+
+func NewBlackboard() *Blackboard {
+	return &Blackboard{values: make(map[string]interface{})}
+}
+
+func (b *Blackboard) Set(key string, value interface{}) {
+	b.values[key] = value
+}
+
+func (b *Blackboard) Get(key string) (interface{}, bool) {
+	v, exists := b.values[key]
+	return v, exists
+}
+
+func (b *Blackboard) Has(key string) bool {
+	_, exists := b.values[key]
+	return exists
+}
+
+func (b *Blackboard) Delete(key string) {
+	delete(b.values, key)
+}
+
+func (b *Blackboard) Clear() {
+	b.values = make(map[string]interface{})
+}
+
+// Clone returns a copy of the blackboard, the values themselves are not copied.
+func (b *Blackboard) Clone() *Blackboard {
+	nb := NewBlackboard()
+	if b != nil {
+		for k, v := range b.values {
+			nb.values[k] = v
+		}
+	}
+	return nb
+}
+
+// BlackboardValue returns the value of key if it exists and is a T.
+func BlackboardValue[T any](b *Blackboard, key string) (T, bool) {
+	v, ok := b.values[key].(T)
+	return v, ok
+}
+
+/*
+	Routines are the old behavior API, a list of functions that run one after the other
+	and are ticked BehaviorTicks times every frame.
+*/
+
 var (
+	// Deprecated: use the BehaviorTree component.
 	Routines []Routiner = make([]Routiner, 0)
 )
 
@@ -49,31 +380,20 @@ func (r *Routine) Run() (Command, bool) {
 	return b, false
 }
 
-func init() {
-	return
-	StartBehavior(Sleep(5), func() Command { println("asd"); return Continue }, Sleep(5), func() Command { println("asd"); return Restart })
-	go func() {
-		for {
-			RunBT(10)
-			time.Sleep(time.Millisecond * 200)
-		}
-	}()
-}
-
 type Routiner interface {
 	Run() (Command, bool)
 }
 
+// Deprecated: use NewTimeout and NewCondition.
 func WaitContinue(fnc RoutineFunc, child Routiner, secTimeout float32) RoutineFunc {
 	started := false
 	var start time.Time
 	return func() Command {
 		if !started {
 			started = true
-			start = time.Now()
+			start = GameTime()
 		}
-		now := time.Now()
-		if now.Sub(start).Seconds() > float64(secTimeout) {
+		if SinceGameTime(start).Seconds() > float64(secTimeout) {
 			started = false
 			return Continue
 		}
@@ -88,6 +408,7 @@ func WaitContinue(fnc RoutineFunc, child Routiner, secTimeout float32) RoutineFu
 	}
 }
 
+// Deprecated: use NewSequence.
 func Sequence(funcs ...RoutineFunc) RoutineFunc {
 	r := NewBehavior(funcs...)
 	return func() Command {
@@ -99,10 +420,12 @@ func Sequence(funcs ...RoutineFunc) RoutineFunc {
 	}
 }
 
+// Deprecated: use NewWait.
 func Sleep(secs float32) RoutineFunc {
 	return WaitContinue(func() Command { return Yield }, nil, secs)
 }
 
+// Deprecated: use NewWaitRandom.
 func SleepRand(secs float32) RoutineFunc {
 	started := false
 	originalValue := secs
@@ -111,11 +434,10 @@ func SleepRand(secs float32) RoutineFunc {
 		NewBehavior(func() Command {
 			if !started {
 				started = true
-				start = time.Now()
+				start = GameTime()
 				secs = rand.Float32() * originalValue
 			}
-			now := time.Now()
-			if now.Sub(start).Seconds() > float64(secs) {
+			if SinceGameTime(start).Seconds() > float64(secs) {
 				started = false
 				return Continue
 			}
@@ -128,6 +450,8 @@ func NewBehavior(funcs ...RoutineFunc) *Routine {
 	return r
 }
 
+// StartBehavior runs the routine until one of its functions returns Close.
+// Deprecated: use the BehaviorTree component, it stops with its game object.
 func StartBehavior(funcs ...RoutineFunc) *Routine {
 	r := NewBehavior(funcs...)
 	found := false
@@ -0,0 +1,106 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+// countedAction returns an action that is Running for running ticks and then returns last.
+func countedAction(running int, last Status, ticks *int) *ActionNode {
+	return NewAction(func(bt *BehaviorTree) Status {
+		*ticks++
+		if *ticks <= running {
+			return StatusRunning
+		}
+		*ticks = 0
+		return last
+	})
+}
+
+func TestBehaviorTreeNodes(t *testing.T) {
+	var a, b, c int
+	bt := NewBehaviorTree(NewSelector(
+		NewSequence(countedAction(1, StatusSuccess, &a), NewInverter(countedAction(0, StatusSuccess, &b))),
+		NewRetry(2, countedAction(0, StatusFailure, &c)),
+	))
+	expected := []Status{StatusRunning, StatusRunning, StatusFailure}
+	for i, e := range expected {
+		if s := bt.Tick(); s != e {
+			t.Fatalf("tick %d returned %v, expected %v", i, s, e)
+		}
+	}
+
+	bt.Blackboard.Set("hits", 0)
+	parallel := NewBehaviorTree(NewParallel(RequireAll,
+		NewRepeat(3, NewAction(func(bt *BehaviorTree) Status {
+			hits, _ := BlackboardValue[int](bt.Blackboard, "hits")
+			bt.Blackboard.Set("hits", hits+1)
+			return StatusSuccess
+		})),
+		NewCondition(func(bt *BehaviorTree) bool { return true }, nil),
+	))
+	parallel.Blackboard = bt.Blackboard.Clone()
+	for i := 0; i < 2; i++ {
+		if s := parallel.Tick(); s != StatusRunning {
+			t.Fatalf("parallel returned %v before the repeat was done", s)
+		}
+	}
+	if s := parallel.Tick(); s != StatusSuccess {
+		t.Fatalf("parallel returned %v, expected Success", s)
+	}
+	if hits, _ := BlackboardValue[int](parallel.Blackboard, "hits"); hits != 3 {
+		t.Errorf("the repeated action ran %d times", hits)
+	}
+	if hits, _ := BlackboardValue[int](bt.Blackboard, "hits"); hits != 0 {
+		t.Error("a cloned blackboard changed the original")
+	}
+}
+
+func TestBehaviorTreeComponent(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	g := h.Scene().(*coroutineScene).owner
+
+	attacks, timeouts := 0, 0
+	bt := NewBehaviorTree(NewSelector(
+		NewCooldown(0.5, NewAction(func(bt *BehaviorTree) Status {
+			attacks++
+			return StatusSuccess
+		})),
+		NewTimeout(0.25, NewWait(1)),
+		NewAction(func(bt *BehaviorTree) Status {
+			timeouts++
+			return StatusSuccess
+		}),
+	))
+	g.AddComponent(bt)
+
+	h.Step(10)
+	if attacks != 1 || timeouts != 0 {
+		t.Fatalf("%d attacks and %d timeouts before the timeout, expected one attack", attacks, timeouts)
+	}
+	h.Step(10)
+	if attacks != 1 || timeouts != 1 {
+		t.Fatalf("%d attacks and %d timeouts after the timeout, expected one of each", attacks, timeouts)
+	}
+	//The cooldown ends while the second timeout runs, the attack comes after it.
+	h.Step(20)
+	if attacks != 2 || timeouts != 2 {
+		t.Errorf("%d attacks and %d timeouts after the cooldown, expected two of each", attacks, timeouts)
+	}
+
+	clone := g.Clone()
+	h.Scene().SceneBase().AddGameObject(clone)
+	cloned, _ := GetComponent[*BehaviorTree](clone)
+	if cloned.Blackboard == bt.Blackboard || cloned.Root != bt.Root {
+		t.Error("a cloned tree should share the nodes and copy the blackboard")
+	}
+
+	g.Destroy()
+	clone.Destroy()
+	h.Step(2)
+	before := attacks
+	h.Step(60)
+	if attacks != before {
+		t.Error("the tree ran after its game object was destroyed")
+	}
+}
@@ -61,20 +61,30 @@ func (c *Camera) ScreenToWorld(x, y int) Vector {
 	return m.Translation()
 }
 
+// currentCamera returns the camera of the scene that is drawn, a scene without a camera
+// (a HUD loaded with LoadSceneAdditive) is drawn with the camera of the main scene.
+func currentCamera() *Camera {
+	if s := GetScene(); s != nil && s.SceneBase().Camera != nil {
+		return s.SceneBase().Camera
+	}
+	if mainScene != nil {
+		return mainScene.SceneBase().Camera
+	}
+	return nil
+}
+
 func (c *Camera) Render() {
 	s := GetScene()
 	if s != nil {
 		tcam := s.SceneBase().Camera
 		s.SceneBase().Camera = c
 		arr := s.SceneBase().gameObjects
-		if arr == nil {
-			println("arr")
-		}
 		if c.GameObject() == nil {
-			println("c.GameObject()")
+			LogRender.Warn("Rendering a camera without a game object")
 		}
 
-		IterExcept(arr, drawGameObject, c.GameObject())
+		except := c.GameObject()
+		runPhase(arr, func(g *GameObject) bool { return g != except }, drawComponent)
 		s.SceneBase().Camera = tcam
 	}
 }
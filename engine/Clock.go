@@ -0,0 +1,182 @@
+package engine
+
+import (
+	"time"
+)
+
+// Clock is the single source of time for the engine.
+// Run calls Tick once per frame, everything else should read the time through DeltaTime/GameTime
+// so pausing, slow motion and deterministic stepping work everywhere.
+type Clock interface {
+	Tick()
+	Reset()
+
+	DeltaTime() float64
+	UnscaledDeltaTime() float64
+	Time() time.Duration
+	UnscaledTime() time.Duration
+
+	TimeScale() float64
+	SetTimeScale(scale float64)
+
+	Pause()
+	Resume()
+	Paused() bool
+}
+
+// GameClock is the default Clock, it follows the wall clock or, in manual mode,
+// advances by a fixed step every Tick.
+type GameClock struct {
+	timeScale float64
+	paused    bool
+
+	manual bool
+	step   time.Duration
+	last   time.Time
+
+	delta, unscaledDelta time.Duration
+	time, unscaledTime   time.Duration
+}
+
+var clock Clock = NewClock()
+
+func NewClock() *GameClock {
+	return &GameClock{timeScale: 1, last: time.Now()}
+}
+
+// NewManualClock creates a clock that ignores the wall clock and advances exactly step every Tick.
+func NewManualClock(step time.Duration) *GameClock {
+	return &GameClock{timeScale: 1, manual: true, step: step}
+}
+
+func (c *GameClock) Tick() {
+	if c.manual {
+		c.unscaledDelta = c.step
+	} else {
+		now := time.Now()
+		c.unscaledDelta = now.Sub(c.last)
+		c.last = now
+	}
+
+	if c.paused {
+		c.delta = 0
+	} else {
+		c.delta = time.Duration(float64(c.unscaledDelta) * c.timeScale)
+	}
+
+	c.time += c.delta
+	c.unscaledTime += c.unscaledDelta
+}
+
+// Reset restarts the frame delta measurement, it does not change the accumulated time.
+func (c *GameClock) Reset() {
+	c.last = time.Now()
+	c.delta = 0
+	c.unscaledDelta = 0
+}
+
+func (c *GameClock) SetStep(step time.Duration) {
+	c.step = step
+}
+
+func (c *GameClock) StepDuration() time.Duration {
+	return c.step
+}
+
+func (c *GameClock) SetManual(manual bool) {
+	c.manual = manual
+	c.last = time.Now()
+}
+
+func (c *GameClock) Manual() bool {
+	return c.manual
+}
+
+func (c *GameClock) DeltaTime() float64 {
+	return c.delta.Seconds()
+}
+
+func (c *GameClock) UnscaledDeltaTime() float64 {
+	return c.unscaledDelta.Seconds()
+}
+
+func (c *GameClock) Time() time.Duration {
+	return c.time
+}
+
+func (c *GameClock) UnscaledTime() time.Duration {
+	return c.unscaledTime
+}
+
+func (c *GameClock) TimeScale() float64 {
+	return c.timeScale
+}
+
+func (c *GameClock) SetTimeScale(scale float64) {
+	if scale < 0 {
+		scale = 0
+	}
+	c.timeScale = scale
+}
+
+func (c *GameClock) Pause() {
+	c.paused = true
+}
+
+func (c *GameClock) Resume() {
+	c.paused = false
+}
+
+func (c *GameClock) Paused() bool {
+	return c.paused
+}
+
+func SetClock(c Clock) {
+	clock = c
+}
+
+func GetClock() Clock {
+	return clock
+}
+
+func DeltaTime() float64 {
+	return clock.DeltaTime()
+}
+
+func UnscaledDeltaTime() float64 {
+	return clock.UnscaledDeltaTime()
+}
+
+// GameTime is the scaled time since the engine started, it stops while the clock is paused.
+func GameTime() time.Time {
+	return time.Time{}.Add(clock.Time())
+}
+
+func UnscaledGameTime() time.Time {
+	return time.Time{}.Add(clock.UnscaledTime())
+}
+
+// SinceGameTime is like time.Since but in game time.
+func SinceGameTime(t time.Time) time.Duration {
+	return GameTime().Sub(t)
+}
+
+func TimeScale() float64 {
+	return clock.TimeScale()
+}
+
+func SetTimeScale(scale float64) {
+	clock.SetTimeScale(scale)
+}
+
+func Pause() {
+	clock.Pause()
+}
+
+func Resume() {
+	clock.Resume()
+}
+
+func Paused() bool {
+	return clock.Paused()
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestManualClock(t *testing.T) {
+	c := NewManualClock(time.Second / 10)
+	c.Tick()
+	if c.DeltaTime() != 0.1 || c.Time() != time.Second/10 {
+		t.Fatalf("expected 0.1 delta, got %f %v", c.DeltaTime(), c.Time())
+	}
+
+	c.SetTimeScale(0.5)
+	c.Tick()
+	if c.DeltaTime() != 0.05 || c.UnscaledDeltaTime() != 0.1 {
+		t.Errorf("expected 0.05 scaled and 0.1 unscaled delta, got %f %f", c.DeltaTime(), c.UnscaledDeltaTime())
+	}
+
+	c.Pause()
+	c.Tick()
+	if c.DeltaTime() != 0 || c.Time() != 150*time.Millisecond {
+		t.Errorf("paused clock moved: %f %v", c.DeltaTime(), c.Time())
+	}
+	if c.UnscaledTime() != 300*time.Millisecond {
+		t.Errorf("unscaled time should keep running while paused, got %v", c.UnscaledTime())
+	}
+
+	c.Resume()
+	c.Tick()
+	if c.Time() != 200*time.Millisecond {
+		t.Errorf("expected 200ms after resume, got %v", c.Time())
+	}
+}
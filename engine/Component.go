@@ -6,7 +6,12 @@ import (
 
 type BaseComponent struct {
 	hasStarted bool
+	disabled   bool
+	order      int
+	hasOrder   bool
 	gameObject *GameObject
+	//The component that embeds this BaseComponent.
+	self Component
 }
 
 func NewComponent() BaseComponent {
@@ -15,6 +20,7 @@ func NewComponent() BaseComponent {
 
 func (c *BaseComponent) onAdd(component Component, gameObject *GameObject) {
 	c.gameObject = gameObject
+	c.self = component
 	component.OnComponentBind(gameObject)
 }
 
@@ -29,6 +35,30 @@ func (c *BaseComponent) Transform() *Transform {
 	return c.gameObject.Transform()
 }
 
+// Enabled reports if the component gets Start, Update, Draw and the other callbacks, components are enabled when they are created.
+func (c *BaseComponent) Enabled() bool {
+	return !c.disabled
+}
+
+// SetEnabled enables or disables the component, OnEnable/OnDisable are called if its game object is active in the hierarchy.
+func (c *BaseComponent) SetEnabled(enabled bool) {
+	if c.disabled == !enabled {
+		return
+	}
+	c.disabled = !enabled
+	if !enabled {
+		c.StopCoroutines()
+	}
+	if c.self == nil || c.gameObject == nil || !c.gameObject.ActiveInHierarchy() {
+		return
+	}
+	if enabled {
+		c.self.OnEnable()
+	} else {
+		c.self.OnDisable()
+	}
+}
+
 /*
 type CollisionCallback interface {
 	OnCollisionEnter(arbiter Arbiter) bool
@@ -58,6 +88,18 @@ type Component interface {
 
 	OnComponentBind(binded *GameObject)
 	OnDestroy()
+
+	//OnEnable and OnDisable are called when an added component starts or stops getting callbacks,
+	//because SetEnabled was called or its game object became active or inactive in the hierarchy.
+	OnEnable()
+	OnDisable()
+	Enabled() bool
+	SetEnabled(enabled bool)
+
+	GameObject() *GameObject
+	Transform() *Transform
+
+	executionOrder() (int, bool)
 	started() bool
 	setStarted(b bool)
 	setGameObject(gobj *GameObject)
@@ -139,3 +181,11 @@ func (c *BaseComponent) OnComponentBind(binded *GameObject) {
 func (c *BaseComponent) OnDestroy() {
 
 }
+
+func (c *BaseComponent) OnEnable() {
+
+}
+
+func (c *BaseComponent) OnDisable() {
+
+}
@@ -0,0 +1,62 @@
+package engine
+
+// GetComponent returns the first component of g that is a T, T can be a component type (*Sprite) or an interface.
+func GetComponent[T any](g *GameObject) (T, bool) {
+	for _, c := range g.components {
+		if t, ok := c.(T); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// GetComponents returns every component of g that is a T.
+func GetComponents[T any](g *GameObject) []T {
+	var comps []T
+	for _, c := range g.components {
+		if t, ok := c.(T); ok {
+			comps = append(comps, t)
+		}
+	}
+	return comps
+}
+
+// GetComponentInChildren searches g and then its children depth first, inactive game objects are skipped.
+func GetComponentInChildren[T any](g *GameObject) (T, bool) {
+	if g.active {
+		if t, ok := GetComponent[T](g); ok {
+			return t, true
+		}
+		for _, c := range g.transform.children {
+			if t, ok := GetComponentInChildren[T](c.gameObject); ok {
+				return t, true
+			}
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// GetComponentInParent searches g and then its parents up to the root.
+func GetComponentInParent[T any](g *GameObject) (T, bool) {
+	for t := g.transform; t != nil; t = t.parent {
+		if c, ok := GetComponent[T](t.gameObject); ok {
+			return c, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// RemoveComponent removes the first component of g that is a T and returns it, OnDestroy is called on it.
+func RemoveComponent[T any](g *GameObject) (T, bool) {
+	for i, c := range g.components {
+		if t, ok := c.(T); ok {
+			g.removeComponentAt(i)
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
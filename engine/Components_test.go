@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"reflect"
+	"testing"
+)
+
+type destroyCounter struct {
+	BaseComponent
+	destroyed int
+}
+
+func (c *destroyCounter) OnDestroy() {
+	c.destroyed++
+}
+
+func TestGetComponent(t *testing.T) {
+	root := NewGameObject("Root")
+	child := NewGameObject("Child")
+	child.Transform().SetParent2(root)
+	rc := root.AddComponent(&destroyCounter{BaseComponent: NewComponent()}).(*destroyCounter)
+	cc := child.AddComponent(&destroyCounter{BaseComponent: NewComponent()}).(*destroyCounter)
+
+	if c, ok := GetComponent[*destroyCounter](root); !ok || c != rc {
+		t.Error("GetComponent did not find the component")
+	}
+	if _, ok := GetComponent[*Sprite](root); ok {
+		t.Error("GetComponent found a missing component")
+	}
+	if c, ok := GetComponentInParent[*destroyCounter](child); !ok || c != cc {
+		t.Error("GetComponentInParent should check the game object first")
+	}
+	if c, ok := GetComponentInChildren[*destroyCounter](root); !ok || c != rc {
+		t.Error("GetComponentInChildren should check the game object first")
+	}
+	child.SetActive(false)
+	if _, ok := GetComponentInChildren[*destroyCounter](child); ok {
+		t.Error("GetComponentInChildren should skip inactive game objects")
+	}
+	if l := len(GetComponents[Component](root)); l != 1 {
+		t.Errorf("GetComponents returned %d components, expected 1", l)
+	}
+}
+
+func TestRemoveComponent(t *testing.T) {
+	g := NewGameObject("Remove")
+	g.AddComponent(NewPhysics(false, 1, 1))
+	a := g.AddComponent(&destroyCounter{BaseComponent: NewComponent()}).(*destroyCounter)
+	b := g.AddComponent(&destroyCounter{BaseComponent: NewComponent()}).(*destroyCounter)
+
+	if _, ok := RemoveComponent[*Physics](g); !ok || g.Physics != nil {
+		t.Error("RemoveComponent should clear the Physics field")
+	}
+	g.RemoveComponentsOfType(reflect.TypeOf(a))
+	if len(g.components) != 0 {
+		t.Errorf("%d components left, expected 0", len(g.components))
+	}
+	if a.destroyed != 1 || b.destroyed != 1 {
+		t.Error("removed components should get OnDestroy")
+	}
+	if _, ok := RemoveComponent[*destroyCounter](g); ok {
+		t.Error("RemoveComponent removed a missing component")
+	}
+}
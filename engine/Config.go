@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"time"
+)
+
+// Config holds the engine settings, it is loaded from a JSON file and command line flags override it:
+//
+//	cfg := engine.DefaultConfig()
+//	cfg.BindFlags(flag.CommandLine)
+//	flag.Parse()
+//	err := cfg.LoadFile("./config.json", flag.CommandLine)
+//	engine.ApplyConfig(cfg)
+//	engine.StartEngine()
+type Config struct {
+	Width  int
+	Height int
+	Title  string
+	VSync  bool
+
+	//PhysicsSteps is the number of physics steps in every 1/60 of a second.
+	PhysicsSteps int
+	//MaxPhysicsTime is the time in seconds the physics steps of one frame can take before the rest are skipped.
+	MaxPhysicsTime float64
+	BehaviorTicks  int
+
+	//ServerAddr is the address the game server listens on and ConnectAddr the one clients connect to.
+	ServerAddr  string
+	ConnectAddr string
+
+	//HotReload reloads textures, atlases and shaders when their files change, for development.
+	HotReload bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Width:          1280,
+		Height:         720,
+		Title:          "Engine Test",
+		VSync:          true,
+		PhysicsSteps:   1,
+		MaxPhysicsTime: float64(1) / float64(30),
+		BehaviorTicks:  5,
+		ServerAddr:     "0.0.0.0:123",
+		ConnectAddr:    "localhost:123",
+	}
+}
+
+var config = DefaultConfig()
+
+// CurrentConfig returns the config that was applied last.
+func CurrentConfig() Config {
+	return config
+}
+
+// BindFlags adds a flag for every field of the config to fs, parsing fs changes c.
+func (c *Config) BindFlags(fs *flag.FlagSet) {
+	fs.IntVar(&c.Width, "width", c.Width, "window width")
+	fs.IntVar(&c.Height, "height", c.Height, "window height")
+	fs.StringVar(&c.Title, "title", c.Title, "window title")
+	fs.BoolVar(&c.VSync, "vsync", c.VSync, "wait for vertical sync")
+	fs.IntVar(&c.PhysicsSteps, "physics-steps", c.PhysicsSteps, "physics steps every 1/60 of a second")
+	fs.Float64Var(&c.MaxPhysicsTime, "max-physics-time", c.MaxPhysicsTime, "seconds the physics of a frame can take")
+	fs.IntVar(&c.BehaviorTicks, "behavior-ticks", c.BehaviorTicks, "behavior tree ticks every frame")
+	fs.StringVar(&c.ServerAddr, "server-addr", c.ServerAddr, "address the server listens on")
+	fs.StringVar(&c.ConnectAddr, "connect-addr", c.ConnectAddr, "address clients connect to")
+	fs.BoolVar(&c.HotReload, "hot-reload", c.HotReload, "reload assets when their files change")
+}
+
+// LoadFile reads the JSON file at path over c, flags of fs that were set on the command line keep their value.
+// fs can be nil, a missing file returns an error that os.IsNotExist accepts and leaves c unchanged.
+func (c *Config) LoadFile(path string, fs *flag.FlagSet) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	set := make(map[string]string)
+	if fs != nil {
+		fs.Visit(func(fl *flag.Flag) {
+			set[fl.Name] = fl.Value.String()
+		})
+	}
+	if err := json.NewDecoder(f).Decode(c); err != nil {
+		return err
+	}
+	for name, value := range set {
+		fs.Set(name, value)
+	}
+	return nil
+}
+
+// SaveFile writes c as JSON.
+func (c *Config) SaveFile(path string) error {
+	data, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ApplyConfig sets the engine globals from c, the window size and vsync are used when the engine starts.
+func ApplyConfig(c Config) {
+	if c.PhysicsSteps < 1 {
+		c.PhysicsSteps = 1
+	}
+	config = c
+	Width, Height = c.Width, c.Height
+	SetTitle(c.Title)
+	VSync = c.VSync
+	steps = float64(c.PhysicsSteps)
+	stepTime = float64(1) / float64(60) / steps
+	maxPhysicsTime = c.MaxPhysicsTime
+	BehaviorTicks = c.BehaviorTicks
+	if c.HotReload {
+		EnableHotReload(time.Second / 2)
+	} else if HotReloadEnabled() {
+		DisableHotReload()
+	}
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigFlagsOverrideFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(`{"Width": 800, "Height": 600, "Title": "From file"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := DefaultConfig()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c.BindFlags(fs)
+	if err := fs.Parse([]string{"-width", "1024", "-vsync=false"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.LoadFile(path, fs); err != nil {
+		t.Fatal(err)
+	}
+	if c.Width != 1024 || c.Height != 600 || c.Title != "From file" || c.VSync {
+		t.Errorf("config is %+v, expected the flags over the file", c)
+	}
+	if c.BehaviorTicks != DefaultConfig().BehaviorTicks {
+		t.Error("fields missing in the file should keep their value")
+	}
+
+	if err := c.LoadFile(filepath.Join(t.TempDir(), "missing.json"), nil); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+}
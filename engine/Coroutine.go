@@ -2,6 +2,7 @@ package engine
 
 import (
 	"fmt"
+	"iter"
 	"runtime"
 	"time"
 )
@@ -20,107 +21,381 @@ const (
 	Restart = Command(32)
 )
 
+/*
+	Coroutines run on the main thread between frames, a coroutine waits with one of the Wait methods:
+
+		g.StartCoroutine(func(co *engine.Coroutine) interface{} {
+			for i := 0; i < 3; i++ {
+				co.WaitForSeconds(1)
+				shoot()
+			}
+			return nil
+		})
+
+	A coroutine is switched to with iter.Pull, so waiting doesn't go through channels or the go scheduler
+	and doesn't allocate. Coroutines of a game object or a component are stopped when it is destroyed or disabled,
+	the deferred calls of a stopped coroutine run when it stops.
+*/
+
+// CoroutineFunc is the body of a coroutine, the returned value is the result of the coroutine.
+type CoroutineFunc func(co *Coroutine) interface{}
+
+type coWait byte
+
+const (
+	waitNone = coWait(iota)
+	waitFrames
+	waitSeconds
+	waitUnscaledSeconds
+	waitFixedUpdate
+	waitUntil
+	waitCoroutine
+)
+
 var (
-	coroutines        []*Coroutine = make([]*Coroutine, 0, 100)
-	current           *Coroutine
-	runningCoroutines bool = false
+	coroutines []*Coroutine = make([]*Coroutine, 0, 100)
+	//current is the coroutine that is running.
+	current *Coroutine
+
+	//frameNumber and fixedStepNumber count the frames and physics steps that started,
+	//a wait only counts the ones that start after it.
+	frameNumber     int
+	fixedStepNumber int
 )
 
+// coroutineStopped unwinds the body of a coroutine that was stopped while it waited.
+type coroutineStopped struct{}
+
 type Coroutine struct {
-	in       chan Command
-	out      chan Command
+	//State is Running until the coroutine returns or is stopped, then it's Ended.
 	State    Command
 	UserData interface{}
+
+	gameObject *GameObject
+	component  Component
+
+	next    func() (Command, bool)
+	stop    func()
+	yield   func(Command) bool
+	result  interface{}
+	stopped bool
+	//running is true while the coroutine runs, it can start other coroutines that stop it.
+	running bool
+
+	wait   coWait
+	frames int
+	//frame and step are the numbers of the frame and the physics step the coroutine started waiting in.
+	frame int
+	step  int
+	until time.Duration
+	cond  func() bool
+	other *Coroutine
 }
 
-func (gr *Coroutine) WaitForCommand() {
-	act := <-gr.in
-	switch act {
-	case Continue:
-		break
-	case Close:
-		for i, ch := range coroutines {
-			if ch == gr {
-				coroutines[i] = nil
+// StartCoroutine starts a coroutine that belongs to the game object, it is stopped when the game object is destroyed or deactivated.
+// The coroutine runs until its first wait before StartCoroutine returns.
+func (g *GameObject) StartCoroutine(fn CoroutineFunc) *Coroutine {
+	return startCoroutine(fn, g, nil)
+}
+
+// StartCoroutine starts a coroutine that belongs to the component, it is also stopped when the component is disabled.
+func (c *BaseComponent) StartCoroutine(fn CoroutineFunc) *Coroutine {
+	return startCoroutine(fn, c.gameObject, c.self)
+}
+
+// StopCoroutines stops every coroutine of the game object and of its components.
+func (g *GameObject) StopCoroutines() {
+	stopCoroutines(g, nil)
+}
+
+// StopCoroutines stops the coroutines that were started by the component.
+func (c *BaseComponent) StopCoroutines() {
+	if c.self != nil {
+		stopCoroutines(c.gameObject, c.self)
+	}
+}
+
+// StartCoroutine starts a coroutine that doesn't belong to anything, it runs until it returns, it's stopped or the scene changes.
+// Deprecated: use GameObject.StartCoroutine or BaseComponent.StartCoroutine with the Wait methods.
+func StartCoroutine(fnc func()) *Coroutine {
+	return startCoroutine(func(co *Coroutine) interface{} {
+		fnc()
+		return nil
+	}, nil, nil)
+}
+
+func startCoroutine(fn CoroutineFunc, g *GameObject, c Component) *Coroutine {
+	co := &Coroutine{State: Running, gameObject: g, component: c}
+	if g != nil && (!g.valid || g.destoryMark || !g.ActiveInHierarchy()) {
+		LogEngine.Warn("Coroutine started on an inactive game object", "gameObject", g.Name())
+		co.State = Ended
+		return co
+	}
+	co.next, co.stop = iter.Pull(func(yield func(Command) bool) {
+		co.yield = yield
+		defer co.recover()
+		co.result = fn(co)
+	})
+	coroutines = append(coroutines, co)
+	co.resume()
+	return co
+}
+
+func (co *Coroutine) recover() {
+	if p := recover(); p != nil {
+		if _, stopped := p.(coroutineStopped); !stopped {
+			logPanic(p)
+		}
+	}
+}
+
+// resume runs the coroutine until its next wait.
+func (co *Coroutine) resume() {
+	last, lastComponent, lastScene := current, runningComponent, currentScene
+	current = co
+	runningComponent = co.component
+	if sd := co.ownerScene(); sd != nil {
+		//GetScene returns the scene of the owner while it runs.
+		for _, s := range activeScenes {
+			if s.SceneBase() == sd {
+				setCurrentScene(s)
 				break
 			}
 		}
-		panic("")
+	}
+	co.wait = waitNone
+	co.running = true
+	_, running := co.next()
+	co.running = false
+	current, runningComponent = last, lastComponent
+	setCurrentScene(lastScene)
+	if !running {
+		co.end()
 	}
 }
 
-func StartCoroutine(fnc func()) *Coroutine {
-	gr := &Coroutine{make(chan Command), make(chan Command), Running, nil}
-	found := false
-	for i, ch := range coroutines {
-		if ch == nil {
-			coroutines[i] = gr
-			found = true
-			break
+func (co *Coroutine) end() {
+	co.State = Ended
+	co.cond = nil
+	co.other = nil
+}
+
+// Stop stops the coroutine, a coroutine that stops itself ends at its next wait.
+func (co *Coroutine) Stop() {
+	if co.State == Ended || co.stopped {
+		return
+	}
+	co.stopped = true
+	if co.running {
+		return
+	}
+	co.stop()
+	co.end()
+}
+
+func (co *Coroutine) Done() bool {
+	return co.State == Ended
+}
+
+// Result returns the value the coroutine returned, nil until it's done.
+func (co *Coroutine) Result() interface{} {
+	return co.result
+}
+
+func (co *Coroutine) GameObject() *GameObject {
+	return co.gameObject
+}
+
+func (co *Coroutine) suspend(wait coWait) {
+	co.wait = wait
+	co.frame = frameNumber
+	co.step = fixedStepNumber
+	if co.stopped || !co.yield(Yield) {
+		panic(coroutineStopped{})
+	}
+}
+
+// WaitForFrames waits until n frames are done, WaitForFrames(1) continues in the next frame
+// even if the coroutine waits during Start or Update.
+func (co *Coroutine) WaitForFrames(n int) {
+	co.frames = n
+	co.suspend(waitFrames)
+}
+
+// WaitForSeconds waits game time, it is scaled by the time scale and stops while the game is paused.
+func (co *Coroutine) WaitForSeconds(seconds float64) {
+	co.until = clock.Time() + time.Duration(seconds*float64(time.Second))
+	co.suspend(waitSeconds)
+}
+
+// WaitForSecondsUnscaled waits real time.
+func (co *Coroutine) WaitForSecondsUnscaled(seconds float64) {
+	co.until = clock.UnscaledTime() + time.Duration(seconds*float64(time.Second))
+	co.suspend(waitUnscaledSeconds)
+}
+
+// WaitForFixedUpdate continues after the FixedUpdate routines of the next physics step, also when it's called from FixedUpdate.
+func (co *Coroutine) WaitForFixedUpdate() {
+	co.suspend(waitFixedUpdate)
+}
+
+// WaitUntil checks cond every frame and continues when it returns true.
+func (co *Coroutine) WaitUntil(cond func() bool) {
+	if cond() {
+		return
+	}
+	co.cond = cond
+	co.suspend(waitUntil)
+	co.cond = nil
+}
+
+// WaitFor waits until other is done and returns its result.
+func (co *Coroutine) WaitFor(other *Coroutine) interface{} {
+	if other.State != Ended {
+		co.other = other
+		co.suspend(waitCoroutine)
+		co.other = nil
+	}
+	return other.result
+}
+
+// Run runs fn as a part of this coroutine and returns its result, fn can wait like the coroutine itself.
+func (co *Coroutine) Run(fn CoroutineFunc) interface{} {
+	return fn(co)
+}
+
+// ready returns true if the coroutine is done waiting, it is called once a frame (or a physics step for fixed waits).
+func (co *Coroutine) ready(fixed bool) bool {
+	if fixed {
+		return co.wait == waitFixedUpdate && fixedStepNumber > co.step
+	}
+	switch co.wait {
+	case waitFrames:
+		if frameNumber <= co.frame {
+			return false
 		}
+		co.frames--
+		return co.frames <= 0
+	case waitSeconds:
+		return clock.Time() >= co.until
+	case waitUnscaledSeconds:
+		return clock.UnscaledTime() >= co.until
+	case waitUntil:
+		return co.cond()
+	case waitCoroutine:
+		return co.other.State == Ended
+	}
+	return false
+}
+
+// ownerScene returns the scene of the game object of the coroutine, the scene of its root for children.
+func (co *Coroutine) ownerScene() *SceneData {
+	if co.gameObject == nil {
+		return nil
+	}
+	return co.gameObject.Scene()
+}
+
+// orphaned returns true if the owner of the coroutine is gone or disabled.
+func (co *Coroutine) orphaned() bool {
+	g := co.gameObject
+	if g == nil {
+		return false
 	}
-	if !found {
-		coroutines = append(coroutines, gr)
+	if !g.valid || g.destoryMark || !g.ActiveInHierarchy() {
+		return true
 	}
-	go startCoroutine(fnc, gr)
-	return gr
+	return co.component != nil && !co.component.Enabled()
+}
+
+func RunCoroutines() {
+	runCoroutines(false)
 }
 
-func startCoroutine(fnc func(), gr *Coroutine) {
-	defer errorFunc()
-	defer func() { gr.out <- Ended }()
-	gr.WaitForCommand()
-	fnc()
+// runFixedCoroutines continues the coroutines that wait for a fixed update, it's called after every FixedUpdate phase.
+func runFixedCoroutines() {
+	runCoroutines(true)
 }
 
-func errorFunc() {
-	if p := recover(); p != nil && p != "" {
-		fmt.Println(p, PanicPath())
+func runCoroutines(fixed bool) {
+	//Coroutines that are started while running wait for the next pass.
+	n := len(coroutines)
+	for i := 0; i < n; i++ {
+		co := coroutines[i]
+		if co.State == Ended {
+			continue
+		}
+		if co.orphaned() {
+			co.Stop()
+			continue
+		}
+		if sd := co.ownerScene(); sd != nil && !sd.Updating() {
+			continue
+		}
+		if co.ready(fixed) {
+			co.resume()
+		}
+	}
+
+	alive := coroutines[:0]
+	for _, co := range coroutines {
+		if co.State != Ended {
+			alive = append(alive, co)
+		}
+	}
+	for i := len(alive); i < len(coroutines); i++ {
+		coroutines[i] = nil
+	}
+	coroutines = alive
+}
+
+// stopCoroutines stops the coroutines of g and its components, or only the ones of c if it's not nil.
+func stopCoroutines(g *GameObject, c Component) {
+	for _, co := range coroutines {
+		if co.gameObject == g && (c == nil || co.component == c) {
+			co.Stop()
+		}
 	}
 }
 
+// stopAllCoroutines is called when the scene changes.
+func stopAllCoroutines() {
+	for _, co := range coroutines {
+		co.Stop()
+	}
+	coroutines = coroutines[:0]
+}
+
+// CoYieldSkip waits for the next frame.
+// Deprecated: use Coroutine.WaitForFrames.
 func CoYieldSkip() {
-	if !runningCoroutines {
+	if current == nil {
 		return
 	}
-	c := current
-	c.out <- Running
-	c.WaitForCommand()
+	current.WaitForFrames(1)
 }
 
+// Deprecated: use Coroutine.WaitFor.
 func CoYieldCoroutine(gr *Coroutine) {
-	if !runningCoroutines {
+	if current == nil {
 		return
 	}
-	for gr.State != Ended {
-		CoYieldSkip()
-	}
+	current.WaitFor(gr)
 }
 
+// Deprecated: use Coroutine.WaitUntil.
 func CoYieldUntil(Out <-chan Command) {
-	if !runningCoroutines {
+	if current == nil {
 		return
 	}
-	c := current
-	c.out <- Running
-
-	for {
+	current.WaitUntil(func() bool {
 		select {
 		case out := <-Out:
-			if out == Ended {
-				goto work
-			}
-		case in := <-c.in:
-			if in == Close {
-				panic("")
-			} else {
-				c.out <- Running
-			}
+			return out == Ended
+		default:
+			return false
 		}
-	}
-work:
-
-	c.WaitForCommand()
+	})
 }
 
 func NewSignal() Signal {
@@ -131,45 +406,16 @@ func (signal Signal) SendEnd() {
 	signal <- Ended
 }
 
+// Deprecated: use Coroutine.WaitForSeconds.
 func CoSleep(seconds float32) {
-	if !runningCoroutines {
+	if current == nil {
 		return
 	}
-	start := time.Now()
-	for {
-		CoYieldSkip()
-		now := time.Now()
-		if now.Sub(start).Seconds() >= float64(seconds) {
-			break
-		}
-	}
-	/*
-		Signal := NewSignal()
-		go func() {
-			<-time.After(time.Second * 3)
-			Signal.SendEnd()
-		}() 
-		Yield(Signal)
-	*/
-}
-
-func RunCoroutines() {
-	runningCoroutines = true
-	for i, ch := range coroutines {
-		if ch != nil {
-			current = ch
-			ch.in <- Continue
-			state := <-ch.out
-			if state == Ended {
-				coroutines[i] = nil
-				ch.State = Ended
-			}
-		}
-	}
-	runningCoroutines = false
-	current = nil
+	current.WaitForSeconds(float64(seconds))
 }
 
+// PanicPath returns the callers of a recovered panic as "file.go:line, ...".
+// Deprecated: LogPanic logs the full stack with the scene, game object and component.
 func PanicPath() string {
 	fullPath := ""
 	skip := 3
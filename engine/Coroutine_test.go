@@ -0,0 +1,164 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+type coroutineScene struct {
+	*SceneData
+	owner *GameObject
+}
+
+func (s *coroutineScene) New() Scene {
+	return &coroutineScene{SceneData: NewScene("CoroutineScene")}
+}
+
+func (s *coroutineScene) Load() {
+	s.owner = NewGameObject("Owner")
+	s.AddGameObject(s.owner)
+}
+
+func TestCoroutineWaits(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	g := h.Scene().(*coroutineScene).owner
+
+	steps := make([]int, 0)
+	double := func(co *Coroutine) interface{} {
+		co.WaitForFrames(1)
+		return 21 * 2
+	}
+	var result interface{}
+	g.StartCoroutine(func(co *Coroutine) interface{} {
+		steps = append(steps, h.Frames())
+		co.WaitForFrames(2)
+		steps = append(steps, h.Frames())
+		co.WaitForSeconds(0.5)
+		steps = append(steps, h.Frames())
+		result = co.Run(double)
+		co.WaitForFixedUpdate()
+		return result
+	})
+	if len(steps) != 1 {
+		t.Fatal("the coroutine didn't run until its first wait")
+	}
+	h.Step(2)
+	if len(steps) != 2 {
+		t.Fatalf("WaitForFrames(2) continued after %v frames", steps)
+	}
+	h.Step(31)
+	if len(steps) != 3 || result != nil {
+		t.Fatalf("WaitForSeconds(0.5) didn't continue after 31 frames, %v", steps)
+	}
+	h.Step(2)
+	if result != 42 {
+		t.Errorf("nested coroutine returned %v, expected 42", result)
+	}
+}
+
+func TestCoroutineStopsOnDestroy(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	g := h.Scene().(*coroutineScene).owner
+
+	done := false
+	deferred := false
+	waiter := g.StartCoroutine(func(co *Coroutine) interface{} {
+		defer func() { deferred = true }()
+		co.WaitUntil(func() bool { return done })
+		return "done"
+	})
+	other := StartCoroutine(func() {})
+	watcher := startCoroutine(func(co *Coroutine) interface{} {
+		return co.WaitFor(waiter)
+	}, nil, nil)
+
+	h.Step(1)
+	if !other.Done() || waiter.Done() {
+		t.Fatal("the coroutines are in the wrong state")
+	}
+	g.Destroy()
+	if !waiter.Done() || !deferred {
+		t.Error("the coroutine kept running after its game object was destroyed")
+	}
+	h.Step(1)
+	if !watcher.Done() || watcher.Result() != nil {
+		t.Errorf("waiting on a stopped coroutine returned %v", watcher.Result())
+	}
+}
+
+// waitingComponent starts a coroutine from its first Update and its first FixedUpdate,
+// they save the number of calls when they continue.
+type waitingComponent struct {
+	BaseComponent
+	updates, fixedUpdates int
+	framesAt, fixedAt     int
+}
+
+func (c *waitingComponent) Update() {
+	c.updates++
+	if c.updates == 1 {
+		c.StartCoroutine(func(co *Coroutine) interface{} {
+			co.WaitForFrames(1)
+			c.framesAt = c.updates
+			return nil
+		})
+	}
+}
+
+func (c *waitingComponent) FixedUpdate() {
+	c.fixedUpdates++
+	if c.fixedUpdates == 1 {
+		c.StartCoroutine(func(co *Coroutine) interface{} {
+			co.WaitForFixedUpdate()
+			c.fixedAt = c.fixedUpdates
+			return nil
+		})
+	}
+}
+
+func TestCoroutineWaitsFromUpdate(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	g := h.Scene().(*coroutineScene).owner
+	g.AddComponent(NewPhysics(false, 1, 1))
+	c := g.AddComponent(&waitingComponent{BaseComponent: NewComponent()}).(*waitingComponent)
+
+	h.Step(5)
+	if c.framesAt != 2 {
+		t.Errorf("WaitForFrames(1) from Update continued after update %d, expected 2", c.framesAt)
+	}
+	if c.fixedAt != 2 {
+		t.Errorf("WaitForFixedUpdate from FixedUpdate continued after fixed update %d, expected 2", c.fixedAt)
+	}
+}
+
+func TestCoroutineOnChild(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	hud := LoadSceneAdditive(&counterScene{})
+	child := NewGameObject("Child")
+	child.Transform().SetParent2(hud.(*counterScene).counter.GameObject())
+
+	runs := 0
+	var scene Scene
+	child.StartCoroutine(func(co *Coroutine) interface{} {
+		for {
+			runs++
+			scene = GetScene()
+			co.WaitForFrames(1)
+		}
+	})
+	h.Step(1)
+	if runs != 2 || scene != hud {
+		t.Fatalf("the coroutine ran %d times in %v, expected 2 in the scene of its root", runs, scene)
+	}
+
+	hud.SceneBase().SetUpdating(false)
+	h.Step(2)
+	if runs != 2 {
+		t.Errorf("the coroutine ran %d times while its scene didn't update", runs)
+	}
+	hud.SceneBase().SetUpdating(true)
+	h.Step(1)
+	if runs != 3 {
+		t.Errorf("the coroutine ran %d times after its scene updated again, expected 3", runs)
+	}
+}
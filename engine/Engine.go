@@ -1,12 +1,11 @@
 package engine
 
 import (
+	"bytes"
 	"github.com/vova616/gl"
 	//"log"
 	"github.com/vova616/garageEngine/engine/input"
-	//"os"
 	"fmt"
-	"github.com/go-gl/glfw"
 	"github.com/vova616/chipmunk"
 	"github.com/vova616/chipmunk/vect"
 	"math"
@@ -68,6 +67,8 @@ const (
 	RadianConst = math.Pi / 180
 	DegreeConst = 180 / math.Pi
 	MouseTag    = "Mouse"
+
+	internalFPSName = "InternalFPS"
 )
 
 var (
@@ -76,6 +77,8 @@ var (
 	scenes       []Scene = make([]Scene, 0)
 	activeScenes []Scene = make([]Scene, 0)
 	mainScene    Scene
+	currentScene Scene
+	unloadScenes []Scene
 
 	nextScene Scene = nil
 
@@ -83,18 +86,15 @@ var (
 	insideGameloop = false
 
 	Space     *chipmunk.Space = nil
-	deltaTime float64
 	fixedTime float64
-	gameTime  time.Time
 
 	steps          = float64(1)
 	stepTime       = float64(1) / float64(60) / steps
 	maxPhysicsTime = float64(1) / float64(30)
 
-	lastTime time.Time = time.Now()
-
 	EnablePhysics = true
 	Debug         = false
+	VSync         = true
 	InternalFPS   = float64(100)
 
 	BehaviorTicks = 5
@@ -116,19 +116,26 @@ func LoadScene(scene Scene) {
 		nextScene = scene
 		return
 	}
+	if sceneLoader != nil {
+		sceneLoader.cancel()
+		sceneLoader = nil
+	}
 
-	ResourceManager.Release()
-	coroutines = coroutines[:0]
+	switchScene(scene.New(), true)
+}
+
+// switchScene destroys every active scene and loads sn as the main scene, then releases the assets that only the old scenes held.
+func switchScene(sn Scene, prepare bool) {
+	stopAllCoroutines()
 	Routines = Routines[:0]
 
+	unloadScenes = unloadScenes[:0]
+
 	if Space != nil {
-		for _, g := range mainScene.SceneBase().gameObjects {
-			if g != nil {
-				g.Destroy()
-			}
+		for i := len(activeScenes) - 1; i >= 0; i-- {
+			destroyScene(activeScenes[i])
 		}
-		Iter(mainScene.SceneBase().gameObjects, destoyGameObject)
-		mainScene.SceneBase().gameObjects = nil
+		activeScenes = activeScenes[:0]
 		Space.Destory()
 		runtime.GC()
 		Space = chipmunk.NewSpace()
@@ -138,17 +145,41 @@ func LoadScene(scene Scene) {
 
 	input.ClearInput()
 
-	sn := scene.New()
+	setCurrentScene(sn)
+	Assets.setLoading(sn.SceneBase())
+	if prepare {
+		prepareScene(sn)
+	}
 	sn.Load()
+	Assets.setLoading(nil)
+	setCurrentScene(nil)
+	Assets.collect()
 
-	internalFPS := NewGameObject("InternalFPS")
+	internalFPS := NewGameObject(internalFPSName)
 	internalFPS.AddComponent(NewFPS())
 	sn.SceneBase().AddGameObject(internalFPS)
 
 	mainScene = sn
+	setCurrentScene(nil)
+	activeScenes = append(activeScenes, sn)
+}
+
+// setCurrentScene sets the scene GetScene returns while a scene loads or runs (nil for the main scene)
+// and gives the assets that are loaded from now on to it.
+func setCurrentScene(s Scene) {
+	currentScene = s
+	var sd *SceneData
+	if s := GetScene(); s != nil {
+		sd = s.SceneBase()
+	}
+	Assets.setRunning(sd)
 }
 
+// GetScene returns the scene that is being updated/drawn right now, outside of the game loop it's the main scene.
 func GetScene() Scene {
+	if currentScene != nil {
+		return currentScene
+	}
 	return mainScene
 }
 
@@ -165,20 +196,16 @@ func Terminated() {
 }
 
 func Terminate() {
-	glfw.Terminate()
+	if window != nil {
+		window.Close()
+	}
 	ShutdownRecived()
 }
 
-func DeltaTime() float64 {
-	return deltaTime
-}
-
-func GameTime() time.Time {
-	return gameTime
-}
-
 func SetTitle(title string) {
-	glfw.SetWindowTitle(title)
+	if window != nil {
+		window.SetTitle(title)
+	}
 	windowTitle = title
 }
 
@@ -189,54 +216,14 @@ func Title() string {
 func StartEngine() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 	runtime.LockOSThread()
-	fmt.Println("Enginge started!")
-	var err error
-	if err = glfw.Init(); err != nil {
-		panic(err)
-	}
-	fmt.Println("GLFW Initialized!")
-
-	glfw.OpenWindowHint(glfw.Accelerated, 1)
+	LogEngine.Info("Engine started")
 
-	if err = glfw.OpenWindow(Width, Height, 8, 8, 8, 8, 8, 8, glfw.Windowed); err != nil {
+	window = newWindow()
+	if err := window.Open(); err != nil {
 		panic(err)
 	}
 
-	glfw.SetSwapInterval(1) //0 to make FPS Maximum
-	glfw.SetWindowTitle(windowTitle)
-	glfw.SetWindowSizeCallback(onResize)
-	glfw.SetKeyCallback(input.OnKey)
-	glfw.SetCharCallback(input.OnChar)
-	glfw.SetMouseButtonCallback(input.ButtonPress)
-	input.MousePosition = glfw.MousePos
-
-	if err = initGL(); err != nil {
-		panic(err)
-	}
-	fmt.Println("Opengl Initialized!")
-
-	TextureMaterial = NewBasicMaterial(spriteVertexShader, spriteFragmentShader)
-	err = TextureMaterial.Load()
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	SDFMaterial = NewBasicMaterial(sdfVertexShader, sdfFragmentShader)
-	err = SDFMaterial.Load()
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	internalMaterial = NewBasicMaterial(spriteVertexShader, spriteFragmentShader)
-	err = internalMaterial.Load()
-	if err != nil {
-		fmt.Println(err)
-	}
-
-	initDefaultPlane()
-
-	gameTime = time.Time{}
-	lastTime = time.Now()
+	clock.Reset()
 }
 
 func MainLoop() bool {
@@ -247,9 +234,13 @@ func MainLoop() bool {
 		nextScene = nil
 		LoadScene(s)
 	}
+	runMainThread()
+	updateSceneLoader()
+	unloadPendingScenes()
+	runReloads()
 
 	insideGameloop = true
-	if running && glfw.WindowParam(glfw.Opened) == 1 {
+	if running && window.Opened() {
 		Run()
 	} else {
 		return false
@@ -260,59 +251,38 @@ func MainLoop() bool {
 }
 
 func Run() {
-	gl.ClearColor(0, 0, 0, 0)
-	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
-	gl.LoadIdentity()
-
-	gameTime = gameTime.Add(time.Since(lastTime))
-	deltaTime = float64(time.Since(lastTime).Nanoseconds()) / float64(time.Second)
-	lastTime = time.Now()
-	before := time.Now()
-
-	timer := NewTimer()
-	timer.Start()
-
-	var destroyDelta,
-		startDelta,
-		fixedUpdateDelta,
-		physicsDelta,
-		updateDelta,
-		lateUpdateDelta,
-		drawDelta,
-		coroutinesDelta,
-		stepDelta,
-		behaviorDelta,
-		startPhysicsDelta,
-		endPhysicsDelta time.Duration
+	window.Clear()
 
-	if mainScene != nil {
-		fixedTime += deltaTime
-		sd := mainScene.SceneBase()
+	clock.Tick()
+	frameNumber++
+	profiler := DefaultProfiler
+	profiler.BeginFrame()
 
-		arr := sd.gameObjects
+	if mainScene != nil {
+		fixedTime += DeltaTime()
 
-		timer.StartCustom("Destory routines")
-		Iter(arr, destoyGameObject)
-		destroyDelta = timer.StopCustom("Destory routines")
+		profiler.Begin("Destroy routines")
+		iterAllScenes(destoyGameObject)
+		profiler.End()
 
-		timer.StartCustom("Start routines")
-		Iter(arr, startGameObject)
-		startDelta = timer.StopCustom("Start routines")
+		profiler.Begin("Start routines")
+		runScenesPhase(nil, startComponent, updatingScene)
+		profiler.End()
 
 		//
 
-		timer.StartCustom("Physics time")
+		profiler.Begin("Physics")
 		if EnablePhysics {
-			timer.StartCustom("Physics step time")
+			stepStart := time.Now()
 			for fixedTime >= stepTime {
-				timer.StartCustom("FixedUpdate routines")
-				Iter(arr, fixedUdpateGameObject)
-				fixedUpdateDelta = timer.StopCustom("FixedUpdate routines")
-
-				timer.StartCustom("Physics time")
+				fixedStepNumber++
+				profiler.Begin("FixedUpdate routines")
+				runScenesPhase(hasPhysics, fixedUpdateComponent, updatingScene)
+				runFixedCoroutines()
+				profiler.End()
 
 				setPosition := func(g *GameObject) {
-					if g.Physics != nil && !g.Physics.Body.IsStatic() && g.Physics.started() {
+					if g.Physics != nil && !g.Physics.Body.IsStatic() && g.Physics.inSpace {
 						pos := g.Transform().WorldPosition()
 
 						var pAngle vect.Float
@@ -344,14 +314,16 @@ func Run() {
 					}
 				}
 
-				timer.StartCustom("Start Physics Delta")
-				Iter(arr, setPosition)
-				startPhysicsDelta = timer.StopCustom("Start Physics Delta")
+				profiler.Begin("Start Physics")
+				iterScenes(setPosition, nil)
+				profiler.End()
 
-				Space.Step(vect.Float(stepTime))
+				profiler.Begin("Physics step")
+				stepSpace(vect.Float(stepTime))
+				profiler.End()
 				fixedTime -= stepTime
 
-				physicsStepDelta := timer.StopCustom("Physics step time")
+				physicsStepDelta := time.Since(stepStart)
 
 				physicsBreak := false
 				//break if its taking too much time
@@ -362,7 +334,7 @@ func Run() {
 				}
 
 				updatePosition := func(g *GameObject) {
-					if g.Physics != nil && !g.Physics.Body.IsStatic() && g.Physics.started() {
+					if g.Physics != nil && !g.Physics.Body.IsStatic() && g.Physics.inSpace {
 
 						/*
 							When parent changes his position/rotation it changes his children position/rotation too but the physics engine thinks its in different position
@@ -403,92 +375,67 @@ func Run() {
 					}
 				}
 
-				timer.StartCustom("End Physics Delta")
-				Iter(arr, updatePosition)
-				endPhysicsDelta = timer.StopCustom("End Physics Delta")
+				profiler.Begin("End Physics")
+				iterScenes(updatePosition, nil)
+				profiler.End()
 
 				if physicsBreak {
 					break
 				}
 			}
 		}
-		physicsDelta = timer.StopCustom("Physics time")
+		profiler.End()
 
-		timer.StartCustom("Update routines")
-		Iter(arr, udpateGameObject)
-		updateDelta = timer.StopCustom("Update routines")
+		profiler.Begin("Update routines")
+		runScenesPhase(nil, updateComponent, updatingScene)
+		profiler.End()
 
-		timer.StartCustom("LateUpdate routines")
-		Iter(arr, lateudpateGameObject)
-		lateUpdateDelta = timer.StopCustom("LateUpdate routines")
+		profiler.Begin("LateUpdate routines")
+		runScenesPhase(nil, lateUpdateComponent, updatingScene)
+		profiler.End()
 
-		timer.StartCustom("Draw routines")
-		Iter(arr, drawGameObject)
-		drawDelta = timer.StopCustom("Draw routines")
+		profiler.Begin("Draw routines")
+		if window.Renders() {
+			runScenesPhase(nil, drawComponent, drawingScene)
+		}
+		profiler.End()
 
-		timer.StartCustom("coroutines")
+		profiler.Begin("Coroutines")
 		RunCoroutines()
-		coroutinesDelta = timer.StopCustom("coroutines")
+		profiler.End()
 
-		timer.StartCustom("BehaviorTree")
+		profiler.Begin("BehaviorTree")
 		RunBT(BehaviorTicks)
-		behaviorDelta = timer.StopCustom("BehaviorTree")
+		profiler.End()
 
 		input.UpdateInput()
-
-		stepDelta = timer.Stop()
 	}
 
-	timer.StartCustom("SwapBuffers")
-	glfw.SwapBuffers()
-	swapBuffersDelta := timer.StopCustom("SwapBuffers")
+	profiler.Begin("SwapBuffers")
+	window.SwapBuffers()
+	profiler.End()
 
-	now := time.Now()
-	deltaDur := now.Sub(before)
+	recordCounters()
+	profiler.EndFrame()
 
-	if Debug {
-		fmt.Println()
-		fmt.Println("##################")
-		if InternalFPS < 20 {
-			fmt.Println("FPS is lower than 20. FPS:", InternalFPS)
-		} else if InternalFPS < 30 {
-			fmt.Println("FPS is lower than 30. FPS:", InternalFPS)
-		} else if InternalFPS < 40 {
-			fmt.Println("FPS is lower than 40. FPS:", InternalFPS)
+	if Debug && profiler.Enabled {
+		if InternalFPS < 40 {
+			LogEngine.Warn("Low FPS", "fps", InternalFPS)
 		}
-		if stepDelta > 17*time.Millisecond {
-			fmt.Println("StepDelta time is lower than normal")
-		}
-		fmt.Println("Debugging Times:")
-		if (deltaDur.Nanoseconds() / int64(time.Millisecond)) != 0 {
-			fmt.Println("Expected FPS", 1000/(deltaDur.Nanoseconds()/int64(time.Millisecond)))
-		}
-		fmt.Println("Step time", stepDelta)
-		fmt.Println("Destroy time", destroyDelta)
-		fmt.Println("Start time", startDelta)
-		fmt.Println("FixedUpdate time", fixedUpdateDelta)
-		fmt.Println("Update time", updateDelta)
-		fmt.Println("LateUpdate time", lateUpdateDelta)
-		fmt.Println("Draw time", drawDelta)
-		fmt.Println("Delta time", deltaDur, deltaTime)
-		fmt.Println("SwapBuffers time", swapBuffersDelta)
-		fmt.Println("Coroutines time", coroutinesDelta)
-		fmt.Println("BehaviorTree time", behaviorDelta)
-		fmt.Println("------------------")
-		fmt.Println("Physics time:", physicsDelta)
-		fmt.Println("StartDelta time", startPhysicsDelta)
-		fmt.Println("EndDelta time", endPhysicsDelta)
-		fmt.Println("StepTime time", Space.StepTime)
-		fmt.Println("ApplyImpulse time", Space.ApplyImpulsesTime)
-		fmt.Println("ReindexQueryTime time", Space.ReindexQueryTime)
-		fmt.Println("##################")
-		fmt.Println()
+		var frame bytes.Buffer
+		profiler.LastFrame().Print(&frame)
+		LogEngine.Info("Frame times", "frame", frame.String())
+		LogPhysics.Info("Space times", "step", Space.StepTime, "applyImpulses", Space.ApplyImpulsesTime, "reindexQuery", Space.ReindexQueryTime)
 	}
 }
 
+// Iter runs f on the objects and their children, inactive objects and everything under them are skipped.
 func Iter(objs []*GameObject, f func(*GameObject)) {
 	for i := len(objs) - 1; i >= 0; i-- {
 		obj := objs[i]
+		if !obj.active {
+			continue
+		}
 		f(obj)
 		//Checks if the objs array has been changed
 		if obj != objs[i] {
@@ -502,7 +449,7 @@ func Iter(objs []*GameObject, f func(*GameObject)) {
 func Iter2(objs []*Transform, f func(*GameObject)) {
 	for i := len(objs) - 1; i >= 0; i-- {
 		obj := objs[i].GameObject()
-		if obj != nil {
+		if obj != nil && obj.active {
 			obja := objs[i]
 			f(obj)
 			//Checks if the objs array has been changed
@@ -515,20 +462,32 @@ func Iter2(objs []*Transform, f func(*GameObject)) {
 	}
 }
 
-func drawGameObject(gameObject *GameObject) {
-	if !gameObject.active {
-		return
+// IterAll is like Iter but it does not skip inactive objects.
+func IterAll(objs []*GameObject, f func(*GameObject)) {
+	for i := len(objs) - 1; i >= 0; i-- {
+		obj := objs[i]
+		f(obj)
+		//Checks if the objs array has been changed
+		if obj != objs[i] {
+			i++
+		} else {
+			Iter2All(obj.Transform().children, f)
+		}
 	}
-	//mat := gameObject.Transform().Matrix()
-
-	//gl.LoadMatrixf(mat.Ptr())
-
-	l := len(gameObject.components)
-	comps := gameObject.components
+}
 
-	for i := l - 1; i >= 0; i-- {
-		if comps[i].started() {
-			comps[i].Draw()
+func Iter2All(objs []*Transform, f func(*GameObject)) {
+	for i := len(objs) - 1; i >= 0; i-- {
+		obj := objs[i].GameObject()
+		if obj != nil {
+			obja := objs[i]
+			f(obj)
+			//Checks if the objs array has been changed
+			if obja != objs[i] {
+				i++
+			} else {
+				Iter2All(obj.Transform().children, f)
+			}
 		}
 	}
 }
@@ -536,6 +495,9 @@ func drawGameObject(gameObject *GameObject) {
 func IterExcept(objs []*GameObject, f func(*GameObject), except *GameObject) {
 	for i := len(objs) - 1; i >= 0; i-- {
 		obj := objs[i]
+		if !obj.active {
+			continue
+		}
 		if obj != except {
 			f(obj)
 		}
@@ -551,7 +513,7 @@ func IterExcept(objs []*GameObject, f func(*GameObject), except *GameObject) {
 func Iter2Except(objs []*Transform, f func(*GameObject), except *GameObject) {
 	for i := len(objs) - 1; i >= 0; i-- {
 		obj := objs[i].GameObject()
-		if obj == nil {
+		if obj == nil || !obj.active {
 			continue
 		}
 		obja := objs[i]
@@ -567,25 +529,10 @@ func Iter2Except(objs []*Transform, f func(*GameObject), except *GameObject) {
 	}
 }
 
-func startGameObject(gameObject *GameObject) {
-	if !gameObject.active {
-		return
-	}
-	l := len(gameObject.components)
-	comps := gameObject.components
-
-	for i := l - 1; i >= 0; i-- {
-		if !comps[i].started() {
-			comps[i].setStarted(true)
-			comps[i].Start()
-		}
-	}
-}
-
 func destoyGameObject(gameObject *GameObject) {
 	if gameObject.destoryMark {
 		gameObject.destroy()
-		mainScene.SceneBase().RemoveGameObject(gameObject)
+		GetScene().SceneBase().RemoveGameObject(gameObject)
 	}
 }
 
@@ -598,7 +545,9 @@ func onCollisionPreSolveGameObject(gameObject *GameObject, arb Arbiter) bool {
 
 	b := true
 	for i := l - 1; i >= 0; i-- {
-		b = b && comps[i].OnCollisionPreSolve(arb)
+		if comps[i].Enabled() {
+			b = b && comps[i].OnCollisionPreSolve(arb)
+		}
 	}
 	return b
 }
@@ -611,7 +560,7 @@ func onCollisionPostSolveGameObject(gameObject *GameObject, arb Arbiter) {
 	comps := gameObject.components
 
 	for i := l - 1; i >= 0; i-- {
-		if comps[i].started() {
+		if comps[i].started() && comps[i].Enabled() {
 			comps[i].OnCollisionPostSolve(arb)
 		}
 	}
@@ -626,7 +575,7 @@ func onCollisionEnterGameObject(gameObject *GameObject, arb Arbiter) bool {
 
 	b := true
 	for i := l - 1; i >= 0; i-- {
-		if comps[i].started() {
+		if comps[i].started() && comps[i].Enabled() {
 			b = b && comps[i].OnCollisionEnter(arb)
 		}
 	}
@@ -641,7 +590,7 @@ func onCollisionExitGameObject(gameObject *GameObject, arb Arbiter) {
 	comps := gameObject.components
 
 	for i := l - 1; i >= 0; i-- {
-		if comps[i].started() {
+		if comps[i].started() && comps[i].Enabled() {
 			comps[i].OnCollisionExit(arb)
 		}
 	}
@@ -656,7 +605,7 @@ func onMouseEnterGameObject(gameObject *GameObject, arb Arbiter) bool {
 
 	b := true
 	for i := l - 1; i >= 0; i-- {
-		if comps[i].started() {
+		if comps[i].started() && comps[i].Enabled() {
 			b = b && comps[i].OnMouseEnter(arb)
 		}
 	}
@@ -671,55 +620,45 @@ func onMouseExitGameObject(gameObject *GameObject, arb Arbiter) {
 	comps := gameObject.components
 
 	for i := l - 1; i >= 0; i-- {
-		if comps[i].started() {
+		if comps[i].started() && comps[i].Enabled() {
 			comps[i].OnMouseExit(arb)
 		}
 	}
 }
 
-func udpateGameObject(gameObject *GameObject) {
-	if !gameObject.active {
-		return
+func startComponent(c Component) {
+	if !c.started() && c.Enabled() {
+		c.setStarted(true)
+		c.Start()
 	}
+}
 
-	l := len(gameObject.components)
-	comps := gameObject.components
-
-	for i := l - 1; i >= 0; i-- {
-		if comps[i].started() {
-			comps[i].Update()
-		}
+func fixedUpdateComponent(c Component) {
+	if c.started() && c.Enabled() {
+		c.FixedUpdate()
 	}
 }
 
-func lateudpateGameObject(gameObject *GameObject) {
-	if !gameObject.active {
-		return
+func updateComponent(c Component) {
+	if c.started() && c.Enabled() {
+		c.Update()
 	}
+}
 
-	l := len(gameObject.components)
-	comps := gameObject.components
-
-	for i := l - 1; i >= 0; i-- {
-		if comps[i].started() {
-			comps[i].LateUpdate()
-		}
+func lateUpdateComponent(c Component) {
+	if c.started() && c.Enabled() {
+		c.LateUpdate()
 	}
 }
 
-func fixedUdpateGameObject(gameObject *GameObject) {
-	if !gameObject.active || gameObject.Physics == nil {
-		return
+func drawComponent(c Component) {
+	if c.started() && c.Enabled() {
+		c.Draw()
 	}
+}
 
-	l := len(gameObject.components)
-	comps := gameObject.components
-
-	for i := l - 1; i >= 0; i-- {
-		if comps[i].started() {
-			comps[i].FixedUpdate()
-		}
-	}
+func hasPhysics(g *GameObject) bool {
+	return g.Physics != nil
 }
 
 func initGL() (err error) {
@@ -764,7 +703,9 @@ func onResize(w, h int) {
 
 	gl.Viewport(0, 0, w, h)
 
-	if GetScene() != nil && GetScene().SceneBase().Camera != nil {
-		GetScene().SceneBase().Camera.UpdateResolution()
+	for _, s := range activeScenes {
+		if s.SceneBase().Camera != nil {
+			s.SceneBase().Camera.UpdateResolution()
+		}
 	}
 }
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Components run Start, FixedUpdate, Update, LateUpdate and Draw by their execution order, lower runs first.
+// Components with the same order run in the scene order, every component starts at 0.
+//
+//	engine.SetExecutionOrder(&PlayerController{}, -100) //Moves the player before anything else.
+//	engine.SetExecutionOrder(&components.SmoothFollow{}, 100) //Follows after everything moved.
+
+var executionOrders = make(map[reflect.Type]int)
+
+// SetExecutionOrder sets the order of every component with the same type as c.
+func SetExecutionOrder(c Component, order int) {
+	if order == 0 {
+		delete(executionOrders, reflect.TypeOf(c))
+		return
+	}
+	executionOrders[reflect.TypeOf(c)] = order
+}
+
+// ExecutionOrder returns the order of the component, its own order if it was set with SetExecutionOrder on the component and the order of its type otherwise.
+func ExecutionOrder(c Component) int {
+	if order, exists := c.executionOrder(); exists {
+		return order
+	}
+	return executionOrders[reflect.TypeOf(c)]
+}
+
+// SetExecutionOrder overrides the order of this component only.
+func (c *BaseComponent) SetExecutionOrder(order int) {
+	c.order = order
+	c.hasOrder = true
+}
+
+func (c *BaseComponent) executionOrder() (int, bool) {
+	return c.order, c.hasOrder
+}
+
+type orderedComponent struct {
+	order      int
+	component  Component
+	gameObject *GameObject
+}
+
+type byExecutionOrder []orderedComponent
+
+func (a byExecutionOrder) Len() int           { return len(a) }
+func (a byExecutionOrder) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byExecutionOrder) Less(i, j int) bool { return a[i].order < a[j].order }
+
+// Phases can run inside other phases (Camera.Render draws inside Draw), every depth gets its own buffer.
+var (
+	phaseBuffers [][]orderedComponent
+	phaseDepth   int
+)
+
+// runPhase collects the components of the active objects that pass filter and calls call on them by execution order.
+// A component is skipped if it was removed or its game object became inactive before its turn.
+func runPhase(objs []*GameObject, filter func(*GameObject) bool, call func(Component)) {
+	if phaseDepth == len(phaseBuffers) {
+		phaseBuffers = append(phaseBuffers, nil)
+	}
+	comps := phaseBuffers[phaseDepth][:0]
+	phaseDepth++
+	defer func() { phaseDepth-- }()
+
+	ordered := false
+	Iter(objs, func(g *GameObject) {
+		if filter != nil && !filter(g) {
+			return
+		}
+		for i := len(g.components) - 1; i >= 0; i-- {
+			c := g.components[i]
+			order := ExecutionOrder(c)
+			if order != 0 {
+				ordered = true
+			}
+			comps = append(comps, orderedComponent{order, c, g})
+		}
+	})
+	if ordered {
+		sort.Stable(byExecutionOrder(comps))
+	}
+
+	prev := runningComponent
+	for _, oc := range comps {
+		if oc.component.GameObject() != oc.gameObject || !oc.gameObject.ActiveInHierarchy() {
+			continue
+		}
+		runningComponent = oc.component
+		call(oc.component)
+	}
+	runningComponent = prev
+
+	for i := range comps {
+		comps[i] = orderedComponent{}
+	}
+	phaseBuffers[phaseDepth-1] = comps[:0]
+}
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+var orderLog []string
+
+type orderedA struct {
+	BaseComponent
+}
+
+func (c *orderedA) Update() {
+	orderLog = append(orderLog, "A")
+}
+
+type orderedB struct {
+	BaseComponent
+}
+
+func (c *orderedB) Update() {
+	orderLog = append(orderLog, "B")
+}
+
+type orderScene struct {
+	*SceneData
+	a *orderedA
+	b *orderedB
+}
+
+func (s *orderScene) New() Scene {
+	return &orderScene{SceneData: NewScene("OrderScene")}
+}
+
+func (s *orderScene) Load() {
+	s.a = &orderedA{NewComponent()}
+	s.b = &orderedB{NewComponent()}
+	a := NewGameObject("A")
+	a.AddComponent(s.a)
+	b := NewGameObject("B")
+	b.AddComponent(s.b)
+	s.AddGameObject(a, b)
+}
+
+func TestExecutionOrder(t *testing.T) {
+	h := NewHarness(&orderScene{}, time.Second/60)
+	defer h.Close()
+	s := h.Scene().(*orderScene)
+
+	SetExecutionOrder(&orderedA{}, 10)
+	defer SetExecutionOrder(&orderedA{}, 0)
+	orderLog = nil
+	h.Step(1)
+	if len(orderLog) != 2 || orderLog[0] != "B" {
+		t.Errorf("order is %v, expected [B A]", orderLog)
+	}
+
+	s.b.SetExecutionOrder(20)
+	orderLog = nil
+	h.Step(1)
+	if len(orderLog) != 2 || orderLog[0] != "A" {
+		t.Errorf("order is %v, expected [A B]", orderLog)
+	}
+}
@@ -15,8 +15,8 @@ func (sp *FPS) SetAction(action func(float64)) {
 }
 
 func (sp *FPS) Update() {
-	sp.timeleft -= DeltaTime()
-	sp.accum += DeltaTime()
+	sp.timeleft -= UnscaledDeltaTime()
+	sp.accum += UnscaledDeltaTime()
 	sp.frames++
 
 	// Interval ended - update GUI text and start new interval
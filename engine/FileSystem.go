@@ -0,0 +1,212 @@
+package engine
+
+import (
+	"archive/zip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Files is the file system every loader reads from, it starts with the working directory and more file systems
+// are mounted on top of it:
+//
+//	engine.Files.MountZip("", "./assets.pack")
+//	engine.Files.MountDir("data", "./mods/bigger-cookies")
+//	engine.Files.Mount("", embeddedData)
+//
+// The last mount wins, so a mod or a patch only needs the files it changes.
+// Paths are cleaned, "./data/rect.png" and "data/rect.png" are the same file. Absolute paths and paths
+// outside of the working directory ("../x") are opened from the OS.
+var Files = newDefaultVFS()
+
+type vfsMount struct {
+	prefix string
+	fsys   fs.FS
+}
+
+// VFS layers file systems, it implements fs.FS, fs.ReadDirFS, fs.ReadFileFS and fs.StatFS.
+type VFS struct {
+	mutex  sync.RWMutex
+	mounts []vfsMount
+}
+
+func NewVFS() *VFS {
+	return &VFS{}
+}
+
+func newDefaultVFS() *VFS {
+	v := NewVFS()
+	v.Mount("", os.DirFS("."))
+	return v
+}
+
+// Mount adds fsys on top of the mounted file systems, its root is seen as prefix ("" for the root).
+func (v *VFS) Mount(prefix string, fsys fs.FS) {
+	prefix = cleanPath(prefix)
+	if prefix == "." {
+		prefix = ""
+	}
+	v.mutex.Lock()
+	v.mounts = append(v.mounts, vfsMount{prefix, fsys})
+	v.mutex.Unlock()
+}
+
+// MountDir mounts a directory of the OS, the returned file system can be unmounted.
+func (v *VFS) MountDir(prefix, dir string) (fs.FS, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, errors.New("The path is not a directory. " + dir)
+	}
+	fsys := os.DirFS(dir)
+	v.Mount(prefix, fsys)
+	return fsys, nil
+}
+
+// MountZip mounts a zip file, it stays open until it's unmounted.
+func (v *VFS) MountZip(prefix, zipPath string) (fs.FS, error) {
+	r, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	v.Mount(prefix, r)
+	return r, nil
+}
+
+// MountPack mounts a zip file or a directory.
+func (v *VFS) MountPack(prefix, pack string) (fs.FS, error) {
+	if info, err := os.Stat(pack); err == nil && info.IsDir() {
+		return v.MountDir(prefix, pack)
+	}
+	return v.MountZip(prefix, pack)
+}
+
+// Unmount removes fsys, zip files are closed.
+func (v *VFS) Unmount(fsys fs.FS) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+	for i, m := range v.mounts {
+		//File systems like fstest.MapFS can't be compared.
+		if reflect.TypeOf(m.fsys) == reflect.TypeOf(fsys) && reflect.TypeOf(fsys).Comparable() && m.fsys == fsys {
+			v.mounts = append(v.mounts[:i:i], v.mounts[i+1:]...)
+			if c, ok := fsys.(io.Closer); ok {
+				c.Close()
+			}
+			return
+		}
+	}
+}
+
+func cleanPath(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
+
+// vfsPath returns the name inside of the layers, false if the name has to be opened from the OS.
+func vfsPath(name string) (string, bool) {
+	clean := cleanPath(name)
+	return clean, !filepath.IsAbs(name) && fs.ValidPath(clean)
+}
+
+// layers returns the mounts that can have name from the top to the bottom, with the name inside of each mount.
+func (v *VFS) layers(name string) ([]fs.FS, []string) {
+	v.mutex.RLock()
+	defer v.mutex.RUnlock()
+	fss := make([]fs.FS, 0, len(v.mounts))
+	names := make([]string, 0, len(v.mounts))
+	for i := len(v.mounts) - 1; i >= 0; i-- {
+		m := v.mounts[i]
+		rel := name
+		if m.prefix != "" {
+			if name == m.prefix {
+				rel = "."
+			} else if strings.HasPrefix(name, m.prefix+"/") {
+				rel = name[len(m.prefix)+1:]
+			} else {
+				continue
+			}
+		}
+		fss = append(fss, m.fsys)
+		names = append(names, rel)
+	}
+	return fss, names
+}
+
+func (v *VFS) Open(name string) (fs.File, error) {
+	clean, ok := vfsPath(name)
+	if !ok {
+		return os.Open(name)
+	}
+	fss, names := v.layers(clean)
+	for i, fsys := range fss {
+		f, err := fsys.Open(names[i])
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+func (v *VFS) ReadFile(name string) ([]byte, error) {
+	f, err := v.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+func (v *VFS) Stat(name string) (fs.FileInfo, error) {
+	f, err := v.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
+
+// ReadDir merges the directory of every layer, files of upper layers replace the ones with the same name.
+func (v *VFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	clean, ok := vfsPath(name)
+	if !ok {
+		return os.ReadDir(name)
+	}
+	found := false
+	entries := make(map[string]fs.DirEntry)
+	fss, names := v.layers(clean)
+	for i, fsys := range fss {
+		dir, err := fs.ReadDir(fsys, names[i])
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return nil, err
+		}
+		found = true
+		for _, e := range dir {
+			if _, exists := entries[e.Name()]; !exists {
+				entries[e.Name()] = e
+			}
+		}
+	}
+	if !found {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	list := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, e)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name() < list[j].Name() })
+	return list, nil
+}
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestVFSLayers(t *testing.T) {
+	zipPath := filepath.Join(t.TempDir(), "patch.zip")
+	f, err := os.Create(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	z := zip.NewWriter(f)
+	w, _ := z.Create("fire/fire.png")
+	w.Write([]byte("patched"))
+	z.Close()
+	f.Close()
+
+	v := NewVFS()
+	v.Mount("data", fstest.MapFS{
+		"fire/fire.png":   {Data: []byte("base")},
+		"fire/fire_0.png": {Data: []byte("base")},
+	})
+	patch, err := v.MountZip("data", zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data, err := v.ReadFile("./data/fire/fire.png"); err != nil || string(data) != "patched" {
+		t.Errorf("read %q %v, expected the zip to win", data, err)
+	}
+	if data, _ := v.ReadFile("data/fire/fire_0.png"); string(data) != "base" {
+		t.Error("files that are not in the zip should come from the layer under it")
+	}
+	dir, err := v.ReadDir("data/fire")
+	if err != nil || len(dir) != 2 {
+		t.Errorf("expected the directories to merge, got %d entries %v", len(dir), err)
+	}
+
+	v.Unmount(patch)
+	if data, _ := v.ReadFile("data/fire/fire.png"); string(data) != "base" {
+		t.Error("the zip is still mounted")
+	}
+	if _, err := v.Open("rect.png"); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+package engine
+
+import (
+	"strings"
+)
+
+// objectSet is an unordered set of game objects that keeps a slice for fast iteration.
+type objectSet struct {
+	list  []*GameObject
+	index map[*GameObject]int
+}
+
+func (s *objectSet) add(g *GameObject) {
+	if s.index == nil {
+		s.index = make(map[*GameObject]int)
+	}
+	if _, exists := s.index[g]; exists {
+		return
+	}
+	s.index[g] = len(s.list)
+	s.list = append(s.list, g)
+}
+
+func (s *objectSet) remove(g *GameObject) {
+	i, exists := s.index[g]
+	if !exists {
+		return
+	}
+	last := len(s.list) - 1
+	s.list[i] = s.list[last]
+	s.index[s.list[i]] = i
+	s.list[last] = nil
+	s.list = s.list[:last]
+	delete(s.index, g)
+}
+
+var (
+	tagIndex   = make(map[string]*objectSet)
+	layerIndex = make(map[int]*objectSet)
+)
+
+func indexTag(g *GameObject, old string) {
+	if s := tagIndex[old]; s != nil {
+		s.remove(g)
+	}
+	if g.tag == "" {
+		return
+	}
+	s := tagIndex[g.tag]
+	if s == nil {
+		s = &objectSet{}
+		tagIndex[g.tag] = s
+	}
+	s.add(g)
+}
+
+func indexLayer(g *GameObject, old int) {
+	if s := layerIndex[old]; s != nil {
+		s.remove(g)
+	}
+	s := layerIndex[g.layer]
+	if s == nil {
+		s = &objectSet{}
+		layerIndex[g.layer] = s
+	}
+	s.add(g)
+}
+
+// reindex indexes g and its children if they are in a scene and don't wait in a pool, and unindexes them if they aren't.
+// It's called when objects are added to or removed from a scene, change their parent or move in and out of a pool.
+func reindex(g *GameObject) {
+	IterAll([]*GameObject{g}, func(o *GameObject) {
+		inScene := o.valid && o.Scene() != nil && !o.waitingInPool()
+		if inScene && !o.indexed {
+			index(o)
+		} else if !inScene && o.indexed {
+			unindex(o)
+		}
+	})
+}
+
+// waitingInPool returns true if g or one of its parents is a pool instance that waits to be spawned.
+func (g *GameObject) waitingInPool() bool {
+	for t := g.transform; t != nil; t = t.parent {
+		if t.gameObject.inPool {
+			return true
+		}
+	}
+	return false
+}
+
+// index adds g to the tag and layer indexes, SetTag and SetLayer keep an indexed object up to date.
+func index(g *GameObject) {
+	g.indexed = true
+	indexTag(g, g.tag)
+	indexLayer(g, g.layer)
+}
+
+func unindex(g *GameObject) {
+	g.indexed = false
+	if s := tagIndex[g.tag]; s != nil {
+		s.remove(g)
+	}
+	if s := layerIndex[g.layer]; s != nil {
+		s.remove(g)
+	}
+}
+
+func (g *GameObject) Tag() string {
+	return g.tag
+}
+
+func (g *GameObject) SetTag(tag string) {
+	if g.tag == tag {
+		return
+	}
+	old := g.tag
+	g.tag = tag
+	if g.indexed {
+		indexTag(g, old)
+	}
+}
+
+func (g *GameObject) CompareTag(tag string) bool {
+	return g.tag == tag
+}
+
+func (g *GameObject) Layer() int {
+	return g.layer
+}
+
+func (g *GameObject) SetLayer(layer int) {
+	if g.layer == layer {
+		return
+	}
+	old := g.layer
+	g.layer = layer
+	if g.indexed {
+		indexLayer(g, old)
+	}
+}
+
+// Scene returns the scene the game object (or its root) was added to, nil for objects that are not in a scene like prefab templates.
+func (g *GameObject) Scene() *SceneData {
+	for t := g.transform; t != nil; t = t.parent {
+		if t.gameObject.scene != nil {
+			return t.gameObject.scene
+		}
+	}
+	return nil
+}
+
+func inActiveScene(g *GameObject) bool {
+	return g.valid && !g.destoryMark && g.Scene() != nil
+}
+
+// Only objects in a scene are indexed, templates and objects that wait in a pool are not.
+
+// FindWithTag returns an object with the tag from the loaded scenes, nil if there is none.
+func FindWithTag(tag string) *GameObject {
+	if s := tagIndex[tag]; s != nil {
+		for _, g := range s.list {
+			if inActiveScene(g) {
+				return g
+			}
+		}
+	}
+	return nil
+}
+
+// FindAllWithTag returns every object with the tag from the loaded scenes.
+func FindAllWithTag(tag string) []*GameObject {
+	return findAll(tagIndex[tag])
+}
+
+// FindAllWithLayer returns every object on the layer from the loaded scenes.
+func FindAllWithLayer(layer int) []*GameObject {
+	return findAll(layerIndex[layer])
+}
+
+func findAll(s *objectSet) []*GameObject {
+	if s == nil {
+		return nil
+	}
+	objs := make([]*GameObject, 0, len(s.list))
+	for _, g := range s.list {
+		if inActiveScene(g) {
+			objs = append(objs, g)
+		}
+	}
+	return objs
+}
+
+// Find returns the object at the path ("GUI/FPS"), the first name is a root object of one of the loaded scenes
+// and every name after it is a child of the one before.
+func Find(path string) *GameObject {
+	path = strings.Trim(path, "/")
+	root, rest := path, ""
+	if i := strings.Index(path, "/"); i >= 0 {
+		root, rest = path[:i], path[i+1:]
+	}
+	for _, s := range activeScenes {
+		for _, g := range s.SceneBase().gameObjects {
+			if g == nil || g.name != root || g.destoryMark || g.transform.parent != nil {
+				continue
+			}
+			if rest == "" {
+				return g
+			}
+			if t := g.transform.Find(rest); t != nil {
+				return t.gameObject
+			}
+		}
+	}
+	return nil
+}
+
+// Find returns the child at the path relative to t ("Turret/Barrel"), nil if there is none.
+func (t *Transform) Find(path string) *Transform {
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		var next *Transform
+		for _, c := range t.children {
+			if c.gameObject.name == name && !c.gameObject.destoryMark {
+				next = c
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		t = next
+	}
+	return t
+}
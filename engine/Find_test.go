@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+type findScene struct {
+	*SceneData
+}
+
+func (s *findScene) New() Scene {
+	return &findScene{SceneData: NewScene("FindScene")}
+}
+
+func (s *findScene) Load() {
+	gui := NewGameObject("GUI")
+	fps := NewGameObject("FPS")
+	fps.Transform().SetParent2(gui)
+	fps.SetTag("Counter")
+	fps.SetLayer(5)
+	s.AddGameObject(gui)
+}
+
+func TestFind(t *testing.T) {
+	NewHarness(&findScene{}, time.Second/60)
+
+	//Not in a scene, it should not be found.
+	template := NewGameObject("Template")
+	template.SetTag("Counter")
+	if template.indexed {
+		t.Error("an object outside of a scene was indexed")
+	}
+
+	fps := Find("GUI/FPS")
+	if fps == nil || fps.Name() != "FPS" {
+		t.Fatalf("Find returned %v", fps)
+	}
+	if Find("GUI/Missing") != nil || Find("Missing") != nil {
+		t.Error("Find returned an object for a missing path")
+	}
+	if g := FindWithTag("Counter"); g != fps {
+		t.Errorf("FindWithTag returned %v", g)
+	}
+	if all := FindAllWithLayer(5); len(all) != 1 || all[0] != fps {
+		t.Errorf("FindAllWithLayer returned %v", all)
+	}
+
+	fps.SetTag("Other")
+	if len(FindAllWithTag("Counter")) != 0 {
+		t.Error("the tag index was not updated")
+	}
+
+	scene := GetScene().SceneBase()
+	gui := Find("GUI")
+	scene.RemoveGameObject(gui)
+	if fps.indexed || len(FindAllWithLayer(5)) != 0 {
+		t.Error("objects removed from the scene are still indexed")
+	}
+	scene.AddGameObject(gui)
+	if all := FindAllWithTag("Other"); len(all) != 1 || all[0] != fps {
+		t.Errorf("FindAllWithTag returned %v after adding the object back", all)
+	}
+	fps.destroy()
+	if len(FindAllWithTag("Other")) != 0 || len(FindAllWithLayer(5)) != 0 {
+		t.Error("destroyed objects are still indexed")
+	}
+}
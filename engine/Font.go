@@ -5,11 +5,11 @@ import (
 	"image"
 	"image/color"
 	"image/draw"
-	"io/ioutil"
+	"io"
+	"io/fs"
 	//"github.com/go-gl/glfw"
 	//"gl/glu"
 	//"log"
-	"fmt"
 	"math"
 	//"bufio"
 	//"image/png"
@@ -22,6 +22,10 @@ type Font struct {
 	fontSize     float64
 	dpi          int
 	sdf          bool
+
+	//Set by the Prepare functions until Upload is called.
+	image    *image.RGBA
+	readonly bool
 }
 
 type LetterInfo struct {
@@ -73,6 +77,36 @@ func NewFont(fontPath string, size float64) (*Font, error) {
 	return NewFont2(fontPath, size, 72, false, 0, 255)
 }
 
+// PrepareFont renders the font letters without touching OpenGL, Upload has to be called on the main thread before using it.
+func PrepareFont(fontPath string, size float64) (*Font, error) {
+	return PrepareFont2(fontPath, size, 72, false, 0, 255)
+}
+
+func PrepareSDFFont(fontPath string, size float64) (*Font, error) {
+	return PrepareSDFFont3(fontPath, size, 72, false, 0, 255, 16, 32)
+}
+
+// Upload creates the texture of a prepared font.
+func (t *Font) Upload() error {
+	if t.image == nil {
+		return nil
+	}
+	texture, err := NewTexture(t.image, t.image.Pix)
+	if err != nil {
+		return err
+	}
+
+	if t.readonly {
+		texture.SetReadOnly()
+	}
+
+	texture.SetFiltering(Linear, Linear)
+
+	t.Texture = texture
+	t.image = nil
+	return nil
+}
+
 func NewSDFFont(fontPath string, size float64) (*Font, error) {
 	return NewSDFFont2(fontPath, size, 16, 32)
 }
@@ -82,7 +116,18 @@ func NewSDFFont2(fontPath string, size float64, scaler float64, scanRange int) (
 }
 
 func NewSDFFont3(fontPath string, size float64, dpi int, readonly bool, firstRune, lastRune rune, scaler float64, scanRange int) (*Font, error) {
-	fontBytes, err := ioutil.ReadFile(fontPath)
+	font, err := PrepareSDFFont3(fontPath, size, dpi, readonly, firstRune, lastRune, scaler, scanRange)
+	if err != nil {
+		return nil, err
+	}
+	if err = font.Upload(); err != nil {
+		return nil, err
+	}
+	return font, nil
+}
+
+func PrepareSDFFont3(fontPath string, size float64, dpi int, readonly bool, firstRune, lastRune rune, scaler float64, scanRange int) (*Font, error) {
+	fontBytes, err := Files.ReadFile(fontPath)
 	if err != nil {
 		return nil, err
 	}
@@ -138,7 +183,7 @@ func NewSDFFont3(fontPath string, size float64, dpi int, readonly bool, firstRun
 
 	dst := image.NewRGBA(image.Rect(0, 0, ((int(mx/256))/int(scaler))+2+int(osize), (int(pt.Y/256)/int(scaler))+2+int(osize)))
 	dstBounds := dst.Bounds()
-	fmt.Println("atlas size", dstBounds)
+	LogAssets.Debug("Font atlas created", "size", dstBounds)
 
 	c.SetDst(dst)
 	c.SetClip(dstBounds)
@@ -156,7 +201,7 @@ func NewSDFFont3(fontPath string, size float64, dpi int, readonly bool, firstRun
 		mask, offset, err := c.Glyph2(font.Index(r), pt)
 		_ = offset
 		if err != nil {
-			fmt.Println("Rune generation error:", err)
+			LogAssets.Warn("Rune generation failed", "rune", string(r), "err", err)
 			continue
 		}
 
@@ -198,26 +243,59 @@ func NewSDFFont3(fontPath string, size float64, dpi int, readonly bool, firstRun
 		LetterArray[r] = &LetterInfo{bd, realoffy, LeftSideBearing, realWidth, planeW, planeH}
 	}
 
-	texture, err := NewTexture(dst, dst.Pix)
+	return &Font{lettersArray: LetterArray, fontSize: osize, dpi: dpi, sdf: true, image: dst, readonly: readonly}, nil
+
+}
+
+func NewFont2(fontPath string, size float64, dpi int, readonly bool, firstRune, lastRune rune) (*Font, error) {
+	font, err := PrepareFont2(fontPath, size, dpi, readonly, firstRune, lastRune)
 	if err != nil {
 		return nil, err
 	}
-
-	if readonly {
-		texture.SetReadOnly()
+	if err = font.Upload(); err != nil {
+		return nil, err
 	}
+	return font, nil
+}
 
-	texture.SetFiltering(Linear, Linear)
+// NewFontFS loads a font file from fsys.
+func NewFontFS(fsys fs.FS, name string, size float64) (*Font, error) {
+	fontBytes, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return nil, err
+	}
+	return newFontData(fontBytes, size)
+}
 
-	return &Font{texture, LetterArray, osize, dpi, true}, nil
+func NewFontReader(r io.Reader, size float64) (*Font, error) {
+	fontBytes, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return newFontData(fontBytes, size)
+}
 
+func newFontData(fontBytes []byte, size float64) (*Font, error) {
+	font, err := PrepareFontData(fontBytes, size, 72, false, 0, 255)
+	if err != nil {
+		return nil, err
+	}
+	if err = font.Upload(); err != nil {
+		return nil, err
+	}
+	return font, nil
 }
 
-func NewFont2(fontPath string, size float64, dpi int, readonly bool, firstRune, lastRune rune) (*Font, error) {
-	fontBytes, err := ioutil.ReadFile(fontPath)
+func PrepareFont2(fontPath string, size float64, dpi int, readonly bool, firstRune, lastRune rune) (*Font, error) {
+	fontBytes, err := Files.ReadFile(fontPath)
 	if err != nil {
 		return nil, err
 	}
+	return PrepareFontData(fontBytes, size, dpi, readonly, firstRune, lastRune)
+}
+
+// PrepareFontData is PrepareFont2 for the bytes of a font file.
+func PrepareFontData(fontBytes []byte, size float64, dpi int, readonly bool, firstRune, lastRune rune) (*Font, error) {
 	font, err := freetype.ParseFont(fontBytes)
 	if err != nil {
 		return nil, err
@@ -277,7 +355,7 @@ func NewFont2(fontPath string, size float64, dpi int, readonly bool, firstRune,
 
 		mask, offset, err := c.Glyph(font.Index(r), pt)
 		if err != nil {
-			fmt.Println("Rune generation error:", err)
+			LogAssets.Warn("Rune generation failed", "rune", string(r), "err", err)
 			continue
 		}
 		bd := mask.Bounds().Add(offset)
@@ -294,18 +372,7 @@ func NewFont2(fontPath string, size float64, dpi int, readonly bool, firstRune,
 		LetterArray[r] = &LetterInfo{bd, (float32(pt.Y/256) - float32(bd.Max.Y)) / float32(size), LeftSideBearing, realWidth, float32(bd.Dx()) / float32(size), float32(bd.Dy()) / float32(size)}
 	}
 
-	texture, err := NewTexture(dst, dst.Pix)
-	if err != nil {
-		return nil, err
-	}
-
-	if readonly {
-		texture.SetReadOnly()
-	}
-
-	texture.SetFiltering(Linear, Linear)
-
-	return &Font{texture, LetterArray, size, dpi, false}, nil
+	return &Font{lettersArray: LetterArray, fontSize: size, dpi: dpi, image: dst, readonly: readonly}, nil
 
 }
 
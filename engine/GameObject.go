@@ -15,9 +15,24 @@ type GameObject struct {
 	active      bool
 	destoryMark bool
 
-	Tag     string
+	tag     string
+	layer   int
+	indexed bool
+	//Set on objects that were added to a scene with AddGameObject.
+	scene *SceneData
+
 	Physics *Physics
 	Sprite  *Sprite
+
+	subscriptions []*Subscription
+
+	//required holds the components that were added because another component requires them,
+	//until they start a component of the same type that is added replaces them.
+	required []Component
+
+	//Set on objects that were created by a Pool, inPool is true while the object waits to be spawned.
+	pool   *Pool
+	inPool bool
 }
 
 var Nil = &BaseComponent{}
@@ -95,8 +110,18 @@ func (g *GameObject) IsValid() bool {
 	return g.valid
 }
 
+// SetActive sets the active state of the game object itself, the game object and its children get callbacks only
+// when it and all of its parents are active (see ActiveInHierarchy).
 func (g *GameObject) SetActive(active bool) {
+	if g.active == active {
+		return
+	}
+	parent := g.transform.parent
+	parentActive := parent == nil || parent.gameObject.ActiveInHierarchy()
 	g.active = active
+	if parentActive && g.valid {
+		g.activeChanged(active)
+	}
 }
 
 func (g *GameObject) SetActiveRecursive(active bool) {
@@ -107,19 +132,58 @@ func (g *GameObject) SetActiveRecursive(active bool) {
 	}
 }
 
+// IsActive is the same as ActiveSelf.
 func (g *GameObject) IsActive() bool {
 	return g.active
 }
 
+// ActiveSelf returns the state set by SetActive, the game object can still be inactive because of its parents.
+func (g *GameObject) ActiveSelf() bool {
+	return g.active
+}
+
+func (g *GameObject) ActiveInHierarchy() bool {
+	for t := g.transform; t != nil; t = t.parent {
+		if !t.gameObject.active {
+			return false
+		}
+	}
+	return true
+}
+
+// activeChanged calls OnEnable/OnDisable on the enabled components of g and of its children that are active themselves.
+func (g *GameObject) activeChanged(active bool) {
+	if !active {
+		stopCoroutines(g, nil)
+	}
+	for _, c := range g.Components() {
+		if !c.Enabled() {
+			continue
+		}
+		if active {
+			c.OnEnable()
+		} else {
+			c.OnDisable()
+		}
+	}
+	for _, t := range g.transform.Children() {
+		if t.gameObject.active {
+			t.gameObject.activeChanged(active)
+		}
+	}
+}
+
 func (g *GameObject) Destroy() {
 	g.destoryMark = true
 	g.active = false
+	stopCoroutines(g, nil)
 	for _, c := range g.transform.children {
 		c.gameObject.Destroy()
 	}
 }
 
 func (g *GameObject) destroy() {
+	g.unsubscribeAll()
 	l := len(g.components)
 	for i := l - 1; i >= 0; i-- {
 		g.components[i].OnDestroy()
@@ -133,63 +197,103 @@ func (g *GameObject) destroy() {
 		}
 	*/
 	g.Transform().SetParent(nil)
+	unindex(g)
 	g.name = ""
 	//g.transform = nil
 	g.components = nil
+	g.required = nil
 	g.valid = false
 	g.active = false
 	g.Sprite = nil
 	g.Physics = nil
 }
 
+// Clone copies the game object and its children, see Prefab for how components are copied.
 func (g *GameObject) Clone() *GameObject {
+	refs := make(cloneRefs)
+	ng := g.clone(refs)
+	for _, n := range refs {
+		if c, ok := n.(Component); ok {
+			remapFields(reflect.ValueOf(c).Elem(), refs)
+		}
+	}
+	return ng
+}
+
+func (g *GameObject) clone(refs cloneRefs) *GameObject {
 	ng := new(GameObject)
+	refs[g] = ng
 	ng.valid = true
 	ng.active = true
-	ng.transform = g.transform.clone(ng)
+	ng.transform = g.transform.clone(ng, refs)
 	ng.name = g.name + ""
-	ng.Tag = g.Tag
-	ng.components = make([]Component, 0)
+	ng.tag = g.tag
+	ng.layer = g.layer
+	ng.components = make([]Component, 0, len(g.components))
 
 	/*
 		It might be possible to make this a little faster by storing size of each Component in a map and use unsafe to copy the values instead of reflect. 
 		(but this is already done by reflect package so I think it will be waste of time)
 	*/
 	for _, c := range g.components {
-		v := reflect.ValueOf(c).Elem()
-		n := reflect.New(v.Type())
-		n.Elem().Set(v)
-		nc := n.Interface().(Component)
+		nc := copyComponent(c)
+		refs[c] = nc
 		nc.setGameObject(ng)
 		nc.setStarted(false)
-		ng.AddComponent(nc)
 		nc.Clone()
+		//The copies of the required components are added too.
+		ng.addComponent(nc)
 	}
 	return ng
 }
 
+// AddComponent adds com and the components it requires (see ComponentInfo.Require).
+// If a component of the same type was added only because another one requires it and it didn't start yet, com replaces it.
 func (g *GameObject) AddComponent(com Component) Component {
+	addRequiredComponents(g, com, nil)
+	if !g.replaceRequired(com) {
+		g.addComponent(com)
+	}
+	return com
+}
+
+func (g *GameObject) addComponent(com Component) {
 	com.onAdd(com, g)
 	com.setStarted(false)
 	g.components = append(g.components, com)
-	return com
 }
 
-func (g *GameObject) RemoveComponent(com Component) bool {
-	t := reflect.TypeOf(com)
-	for i, c := range g.components {
-		if t == reflect.TypeOf(c) {
-			g.components = append(g.components[:i], g.components[i+1:]...)
+// replaceRequired puts com in the place of a required component of its type, it returns false if there is none.
+func (g *GameObject) replaceRequired(com Component) bool {
+	typ := reflect.TypeOf(com)
+	for i, req := range g.required {
+		if reflect.TypeOf(req) != typ || req.started() {
+			continue
+		}
+		g.required = append(g.required[:i:i], g.required[i+1:]...)
+		for j, c := range g.components {
+			if c != req {
+				continue
+			}
+			g.removeComponentAt(j)
+			com.onAdd(com, g)
+			com.setStarted(false)
+			g.components = append(g.components[:j:j], append([]Component{com}, g.components[j:]...)...)
 			return true
 		}
 	}
 	return false
 }
 
+// RemoveComponent removes the first component with the same type as com.
+func (g *GameObject) RemoveComponent(com Component) bool {
+	return g.RemoveComponentOfType(reflect.TypeOf(com))
+}
+
 func (g *GameObject) RemoveComponentOfType(typ reflect.Type) bool {
 	for i, c := range g.components {
 		if typ == reflect.TypeOf(c) {
-			g.components = append(g.components[:i], g.components[i+1:]...)
+			g.removeComponentAt(i)
 			return true
 		}
 	}
@@ -197,9 +301,25 @@ func (g *GameObject) RemoveComponentOfType(typ reflect.Type) bool {
 }
 
 func (g *GameObject) RemoveComponentsOfType(typ reflect.Type) {
-	for i, c := range g.components {
-		if typ == reflect.TypeOf(c) {
-			g.components = append(g.components[:i], g.components[i+1:]...)
+	for i := len(g.components) - 1; i >= 0; i-- {
+		if typ == reflect.TypeOf(g.components[i]) {
+			g.removeComponentAt(i)
 		}
 	}
 }
+
+// removeComponentAt calls OnDestroy on the component and removes it.
+// The slice is copied so loops that are ranging over the old one are not affected.
+func (g *GameObject) removeComponentAt(i int) Component {
+	c := g.components[i]
+	g.components = append(g.components[:i:i], g.components[i+1:]...)
+	c.OnDestroy()
+	if p, ok := c.(*Physics); ok && g.Physics == p {
+		g.Physics = nil
+	}
+	if s, ok := c.(*Sprite); ok && g.Sprite == s {
+		g.Sprite = nil
+	}
+	c.setGameObject(nil)
+	return c
+}
@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"time"
+)
+
+// Harness drives the engine frame by frame with a fixed delta time.
+// It starts the engine headless if it was not started yet and replaces the engine clock with
+// a manual one, so it can be used from go tests:
+//
+//	h := engine.NewHarness(scene, time.Second/60)
+//	h.Step(60)
+type Harness struct {
+	Clock  *GameClock
+	frames int
+}
+
+func NewHarness(scene Scene, delta time.Duration) *Harness {
+	if window == nil || (Headless && !window.Opened()) {
+		Headless = true
+		StartEngine()
+	}
+	h := &Harness{Clock: NewManualClock(delta)}
+	SetClock(h.Clock)
+	LoadScene(scene)
+	return h
+}
+
+// Step runs n frames and returns false if the window was closed before that.
+func (h *Harness) Step(n int) bool {
+	for i := 0; i < n; i++ {
+		if !MainLoop() {
+			return false
+		}
+		h.frames++
+	}
+	return true
+}
+
+// StepTime runs as many frames as needed to cover d.
+func (h *Harness) StepTime(d time.Duration) bool {
+	return h.Step(int(d / h.Clock.StepDuration()))
+}
+
+func (h *Harness) Frames() int {
+	return h.frames
+}
+
+func (h *Harness) Scene() Scene {
+	return GetScene()
+}
+
+// Close closes the fake window, MainLoop will return false after that.
+func (h *Harness) Close() {
+	window.Close()
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+type counter struct {
+	BaseComponent
+	starts, updates, lateUpdates int
+	delta                        float64
+}
+
+func (c *counter) Start() {
+	c.starts++
+}
+
+func (c *counter) Update() {
+	c.updates++
+	c.delta = DeltaTime()
+}
+
+func (c *counter) LateUpdate() {
+	c.lateUpdates++
+}
+
+type counterScene struct {
+	*SceneData
+	counter *counter
+}
+
+func (s *counterScene) New() Scene {
+	return &counterScene{SceneData: NewScene("CounterScene")}
+}
+
+func (s *counterScene) Load() {
+	s.counter = &counter{BaseComponent: NewComponent()}
+	g := NewGameObject("Counter")
+	g.AddComponent(s.counter)
+	s.AddGameObject(g)
+}
+
+func TestHarnessStep(t *testing.T) {
+	h := NewHarness(&counterScene{}, time.Second/60)
+	if !h.Step(10) {
+		t.Fatal("window closed")
+	}
+	c := h.Scene().(*counterScene).counter
+	if c.starts != 1 {
+		t.Errorf("Start called %d times, expected 1", c.starts)
+	}
+	if c.updates != 10 || c.lateUpdates != 10 {
+		t.Errorf("Update/LateUpdate called %d/%d times, expected 10", c.updates, c.lateUpdates)
+	}
+	if c.delta != (time.Second / 60).Seconds() {
+		t.Errorf("DeltaTime is %f, expected %f", c.delta, (time.Second / 60).Seconds())
+	}
+	if h.Frames() != 10 {
+		t.Errorf("Frames is %d, expected 10", h.Frames())
+	}
+
+	h.Close()
+	if h.Step(1) {
+		t.Error("Step should fail after Close")
+	}
+}
@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+// Hot reload watches the files behind loaded textures, atlases and shaders and loads them again when they change,
+// it is meant for development:
+//
+//	engine.EnableHotReload(time.Second / 2)
+//
+// The files are polled on a background goroutine, the reloads run on the main thread before the next frame.
+// A shader that fails to compile is logged and the old program is kept.
+
+type fileWatch struct {
+	path    string
+	owner   interface{}
+	reload  func() error
+	mod     time.Time
+	size    int64
+	removed bool
+}
+
+var (
+	watchLock   sync.Mutex
+	watches     []*fileWatch
+	reloads     = make(chan *fileWatch, 64)
+	stopWatcher chan struct{}
+)
+
+// watchFile calls reload on the main thread when the file at path changes, owner is used to remove the watch.
+// Files are only watched while hot reload is enabled, the ones loaded before EnableHotReload are not.
+func watchFile(path string, owner interface{}, reload func() error) {
+	if !HotReloadEnabled() {
+		return
+	}
+	w := &fileWatch{path: path, owner: owner, reload: reload}
+	if info, err := Files.Stat(path); err == nil {
+		w.mod, w.size = info.ModTime(), info.Size()
+	}
+	watchLock.Lock()
+	watches = append(watches, w)
+	watchLock.Unlock()
+}
+
+// unwatchFiles removes every watch of owner.
+func unwatchFiles(owner interface{}) {
+	watchLock.Lock()
+	defer watchLock.Unlock()
+	kept := watches[:0]
+	for _, w := range watches {
+		if w.owner == owner {
+			w.removed = true
+		} else {
+			kept = append(kept, w)
+		}
+	}
+	for i := len(kept); i < len(watches); i++ {
+		watches[i] = nil
+	}
+	watches = kept
+}
+
+// EnableHotReload starts polling the watched files every interval.
+func EnableHotReload(interval time.Duration) {
+	DisableHotReload()
+	stop := make(chan struct{})
+	watchLock.Lock()
+	stopWatcher = stop
+	watchLock.Unlock()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				checkWatches()
+			}
+		}
+	}()
+	LogAssets.Info("Hot reload enabled", "interval", interval)
+}
+
+func DisableHotReload() {
+	watchLock.Lock()
+	defer watchLock.Unlock()
+	if stopWatcher != nil {
+		close(stopWatcher)
+		stopWatcher = nil
+	}
+}
+
+// HotReloadEnabled returns true between EnableHotReload and DisableHotReload.
+func HotReloadEnabled() bool {
+	watchLock.Lock()
+	defer watchLock.Unlock()
+	return stopWatcher != nil
+}
+
+// checkWatches stats the watched files and queues the ones that changed, files that are missing are skipped
+// so a save that replaces the file is picked up on the next check.
+func checkWatches() {
+	watchLock.Lock()
+	changed := make([]*fileWatch, 0)
+	for _, w := range watches {
+		info, err := Files.Stat(w.path)
+		if err != nil {
+			continue
+		}
+		if !info.ModTime().Equal(w.mod) || info.Size() != w.size {
+			w.mod, w.size = info.ModTime(), info.Size()
+			changed = append(changed, w)
+		}
+	}
+	watchLock.Unlock()
+
+	for _, w := range changed {
+		select {
+		case reloads <- w:
+		default:
+			//The queue is full, the file is checked again next time.
+			watchLock.Lock()
+			w.mod = time.Time{}
+			watchLock.Unlock()
+		}
+	}
+}
+
+// runReloads runs the queued reloads, it is called by the main loop.
+func runReloads() {
+	for {
+		select {
+		case w := <-reloads:
+			watchLock.Lock()
+			removed := w.removed
+			watchLock.Unlock()
+			if removed {
+				continue
+			}
+			if err := w.reload(); err != nil {
+				LogAssets.Error("Hot reload failed", "path", w.path, "err", err)
+			} else {
+				LogAssets.Info("Hot reloaded", "path", w.path)
+			}
+		default:
+			return
+		}
+	}
+}
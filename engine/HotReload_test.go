@@ -0,0 +1,58 @@
+package engine
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestHotReloadWatches(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "shader.frag")
+	if err := os.WriteFile(path, []byte("void main(void) {}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	EnableHotReload(time.Hour)
+	owner := new(int)
+	calls := 0
+	watchFile(path, owner, func() error {
+		calls++
+		return errors.New("compile error")
+	})
+	defer unwatchFiles(owner)
+
+	//The watcher is stopped, checkWatches is called by the test.
+	DisableHotReload()
+	n := len(watches)
+	watchFile(path, new(int), func() error { return nil })
+	if len(watches) != n {
+		t.Fatal("a file was watched while hot reload was disabled")
+	}
+
+	checkWatches()
+	runReloads()
+	if calls != 0 {
+		t.Fatal("an unchanged file was reloaded")
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	checkWatches()
+	runReloads()
+	if calls != 1 {
+		t.Fatalf("reload was called %d times, expected once", calls)
+	}
+
+	later = later.Add(time.Minute)
+	os.Chtimes(path, later, later)
+	checkWatches()
+	unwatchFiles(owner)
+	runReloads()
+	if calls != 1 {
+		t.Error("a queued reload ran after its owner was removed")
+	}
+}
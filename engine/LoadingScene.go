@@ -0,0 +1,68 @@
+package engine
+
+// LoadingScene is the default loading screen for LoadSceneAsync, it draws the progress of the current scene loader as a bar.
+type LoadingScene struct {
+	*SceneData
+	Bar *LoadingBar
+}
+
+var LoadingSceneGeneral = &LoadingScene{}
+
+func (s *LoadingScene) New() Scene {
+	return &LoadingScene{SceneData: NewScene("LoadingScene")}
+}
+
+func (s *LoadingScene) Load() {
+	s.Camera = NewCamera()
+
+	cam := NewGameObject("Camera")
+	cam.AddComponent(s.Camera)
+
+	bar := NewGameObject("LoadingBar")
+	bar.Transform().SetParent2(cam)
+	bar.Transform().SetPositionf(float32(Width)*0.2, float32(Height)/2)
+	s.Bar = NewLoadingBar(float32(Width)*0.6, 20)
+	bar.AddComponent(s.Bar)
+
+	if !Headless {
+		white := NewRGBATexture([]byte{255, 255, 255, 255}, 1, 1)
+
+		background := NewGameObject("Background")
+		background.Transform().SetParent2(bar)
+		background.Transform().SetScalef(s.Bar.Width, s.Bar.Height)
+		bg := NewSprite(white)
+		bg.SetAlign(AlignLeft)
+		bg.Color = Color{0.2, 0.2, 0.2, 1}
+		background.AddComponent(bg)
+
+		fill := NewGameObject("Fill")
+		fill.Transform().SetParent2(bar)
+		sp := NewSprite(white)
+		sp.SetAlign(AlignLeft)
+		fill.AddComponent(sp)
+		s.Bar.Fill = fill
+	}
+
+	s.AddGameObject(cam)
+}
+
+// LoadingBar scales Fill on the X axis by the progress of the current scene loader.
+type LoadingBar struct {
+	BaseComponent
+	Fill          *GameObject
+	Width, Height float32
+	Progress      float32 `range:"0,1"`
+}
+
+func NewLoadingBar(width, height float32) *LoadingBar {
+	return &LoadingBar{BaseComponent: NewComponent(), Width: width, Height: height}
+}
+
+func (b *LoadingBar) Update() {
+	if l := CurrentSceneLoader(); l != nil {
+		b.Progress = l.Progress()
+	}
+	if b.Fill != nil {
+		b.Fill.Transform().SetScalef(b.Width*b.Progress, b.Height)
+	}
+}
@@ -0,0 +1,117 @@
+package engine
+
+import (
+	"io"
+	"reflect"
+	"runtime/debug"
+
+	"github.com/vova616/garageEngine/engine/logging"
+)
+
+// Loggers write leveled entries with key/value fields to the log sinks, every subsystem has its own category:
+//
+//	engine.LogAssets.Error("Texture loading failed", "path", path, "err", err)
+//	log := engine.NewLogger("ai")
+//	log.Debug("Target changed", "target", target.Name())
+//
+// The loggers live in the logging package so the game servers can log without importing the engine,
+// the names here are kept for the engine and the games.
+
+type (
+	LogLevel         = logging.Level
+	LogEntry         = logging.Entry
+	LogSink          = logging.Sink
+	Logger           = logging.Logger
+	ConsoleSink      = logging.ConsoleSink
+	RotatingFileSink = logging.RotatingFileSink
+	MemorySink       = logging.MemorySink
+)
+
+const (
+	LevelDebug = logging.LevelDebug
+	LevelInfo  = logging.LevelInfo
+	LevelWarn  = logging.LevelWarn
+	LevelError = logging.LevelError
+	LevelFatal = logging.LevelFatal
+)
+
+var (
+	LogEngine  = NewLogger("engine")
+	LogScene   = NewLogger("scene")
+	LogPhysics = NewLogger("physics")
+	LogRender  = NewLogger("render")
+	LogNetwork = NewLogger("network")
+	LogAssets  = NewLogger("assets")
+)
+
+func NewLogger(category string) *Logger {
+	return logging.NewLogger(category)
+}
+
+// ParseLogLevel parses a level name like "debug" or "WARN".
+func ParseLogLevel(name string) (LogLevel, error) {
+	return logging.ParseLevel(name)
+}
+
+func AddLogSink(sink LogSink) {
+	logging.AddSink(sink)
+}
+
+func RemoveLogSink(sink LogSink) {
+	logging.RemoveSink(sink)
+}
+
+// SetLogSinks replaces all of the sinks.
+func SetLogSinks(sinks ...LogSink) {
+	logging.SetSinks(sinks...)
+}
+
+// SetLogLevel sets the level of the categories that don't have their own.
+func SetLogLevel(level LogLevel) {
+	logging.SetLevel(level)
+}
+
+func SetCategoryLevel(category string, level LogLevel) {
+	logging.SetCategoryLevel(category, level)
+}
+
+func NewConsoleSink(w io.Writer) *ConsoleSink {
+	return logging.NewConsoleSink(w)
+}
+
+func NewRotatingFileSink(path string, maxSize int64, keep int) (*RotatingFileSink, error) {
+	return logging.NewRotatingFileSink(path, maxSize, keep)
+}
+
+func NewMemorySink(size int) *MemorySink {
+	return logging.NewMemorySink(size)
+}
+
+// runningComponent is the component a phase is calling, it stays set if the call panics.
+var runningComponent Component
+
+// LogPanic logs a panic with the scene, game object and component that were running and the stack, it has to be deferred:
+//
+//	defer engine.LogPanic()
+//
+// The panic is stopped, use it where the program can go on or terminate on its own.
+func LogPanic() {
+	if p := recover(); p != nil {
+		logPanic(p)
+	}
+}
+
+func logPanic(p interface{}) {
+	fields := []interface{}{"panic", p}
+	if s := GetScene(); s != nil {
+		fields = append(fields, "scene", s.SceneBase().Name())
+	}
+	if c := runningComponent; c != nil {
+		if g := c.GameObject(); g != nil {
+			fields = append(fields, "gameObject", g.Name())
+		}
+		fields = append(fields, "component", reflect.TypeOf(c).String())
+	}
+	fields = append(fields, "stack", string(debug.Stack()))
+	LogEngine.Log(LevelFatal, "Panic", fields...)
+}
@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+type panicking struct {
+	BaseComponent
+}
+
+func TestLogPanicContext(t *testing.T) {
+	mem := NewMemorySink(1)
+	SetLogSinks(mem)
+	defer SetLogSinks(NewConsoleSink(os.Stdout))
+
+	g := NewGameObject("Broken")
+	c := &panicking{NewComponent()}
+	g.AddComponent(c)
+	func() {
+		defer LogPanic()
+		runPhase([]*GameObject{g}, nil, func(Component) { panic("boom") })
+	}()
+	runningComponent = nil
+
+	entries := mem.Entries()
+	if len(entries) != 1 || entries[0].Level != LevelFatal {
+		t.Fatal("the panic was not logged")
+	}
+	s := entries[0].String()
+	if !strings.Contains(s, "gameObject=Broken") || !strings.Contains(s, "component=*engine.panicking") {
+		t.Errorf("panic entry has no context: %s", s)
+	}
+}
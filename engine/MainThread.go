@@ -0,0 +1,181 @@
+package engine
+
+import (
+	"fmt"
+	"sync"
+)
+
+/*
+	Goroutines (network, loaders) don't touch the engine, they send the work to the main thread:
+
+		go func() {
+			packet := read(conn)
+			engine.RunOnMainThread(func() {
+				spawnPlayer(packet)
+			})
+		}()
+
+	The queued functions run at the start of every frame, before the scene loader and before the Destroy phase,
+	so they can load scenes and create or destroy game objects like anything that runs between frames.
+*/
+
+var (
+	mainThreadLock sync.Mutex
+	mainThreadJobs []func()
+)
+
+// RunOnMainThread queues fn to run on the main thread at the start of the next frame, it can be called from any goroutine.
+// Functions run in the order they were queued.
+func RunOnMainThread(fn func()) {
+	mainThreadLock.Lock()
+	mainThreadJobs = append(mainThreadJobs, fn)
+	mainThreadLock.Unlock()
+}
+
+// runMainThread is called by the main loop before every frame, functions that are queued while it runs wait for the next frame.
+func runMainThread() {
+	mainThreadLock.Lock()
+	jobs := mainThreadJobs
+	mainThreadJobs = nil
+	mainThreadLock.Unlock()
+
+	for _, fn := range jobs {
+		runMainThreadJob(fn)
+	}
+}
+
+func runMainThreadJob(fn func()) {
+	defer func() {
+		if p := recover(); p != nil {
+			logPanic(p)
+		}
+	}()
+	fn()
+}
+
+// Future is a value that is ready later, background work completes it and the main thread polls or awaits it:
+//
+//	f := engine.StartTask(func() (interface{}, error) {
+//		return http.Get(url)
+//	})
+//	g.StartCoroutine(func(co *engine.Coroutine) interface{} {
+//		res, err := co.Await(f)
+//		...
+//	})
+type Future struct {
+	mutex     sync.Mutex
+	done      chan struct{}
+	value     interface{}
+	err       error
+	callbacks []func(interface{}, error)
+}
+
+func NewFuture() *Future {
+	return &Future{done: make(chan struct{})}
+}
+
+// StartTask runs fn on a new goroutine and returns a future of its result, a panic in fn is logged and fails the future.
+func StartTask(fn func() (interface{}, error)) *Future {
+	f := NewFuture()
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				logPanic(p)
+				f.Fail(fmt.Errorf("%v", p))
+			}
+		}()
+		f.Complete(fn())
+	}()
+	return f
+}
+
+// MainThreadTask runs fn on the main thread, a goroutine can Wait for its result.
+func MainThreadTask(fn func() (interface{}, error)) *Future {
+	f := NewFuture()
+	RunOnMainThread(func() {
+		defer func() {
+			if p := recover(); p != nil {
+				f.Fail(fmt.Errorf("%v", p))
+				panic(p)
+			}
+		}()
+		f.Complete(fn())
+	})
+	return f
+}
+
+// Complete sets the result of the future, it can be called from any goroutine. Only the first call counts.
+func (f *Future) Complete(value interface{}, err error) {
+	f.mutex.Lock()
+	select {
+	case <-f.done:
+		f.mutex.Unlock()
+		return
+	default:
+	}
+	f.value, f.err = value, err
+	close(f.done)
+	//The callbacks are queued under the lock, so a Then that comes after Complete runs after them.
+	for _, fn := range f.callbacks {
+		f.dispatch(fn)
+	}
+	f.callbacks = nil
+	f.mutex.Unlock()
+}
+
+func (f *Future) Resolve(value interface{}) {
+	f.Complete(value, nil)
+}
+
+func (f *Future) Fail(err error) {
+	f.Complete(nil, err)
+}
+
+func (f *Future) Done() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Result returns the value and the error of the future, both are nil until it's done.
+func (f *Future) Result() (interface{}, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.value, f.err
+}
+
+// Wait blocks until the future is done, never call it on the main thread, coroutines use Coroutine.Await.
+func (f *Future) Wait() (interface{}, error) {
+	<-f.done
+	return f.Result()
+}
+
+// Then calls fn on the main thread when the future is done, in the next frame if it's already done.
+func (f *Future) Then(fn func(value interface{}, err error)) {
+	f.mutex.Lock()
+	select {
+	case <-f.done:
+	default:
+		f.callbacks = append(f.callbacks, fn)
+		f.mutex.Unlock()
+		return
+	}
+	f.mutex.Unlock()
+	f.dispatch(fn)
+}
+
+func (f *Future) dispatch(fn func(interface{}, error)) {
+	value, err := f.value, f.err
+	RunOnMainThread(func() {
+		fn(value, err)
+	})
+}
+
+// Await waits until the future is done and returns its result.
+func (co *Coroutine) Await(f *Future) (interface{}, error) {
+	co.WaitUntil(f.Done)
+	return f.Result()
+}
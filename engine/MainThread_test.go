@@ -0,0 +1,82 @@
+package engine
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRunOnMainThread(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+
+	var wg sync.WaitGroup
+	order := make([]int, 0)
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			RunOnMainThread(func() {
+				order = append(order, len(order))
+			})
+		}()
+	}
+	wg.Wait()
+	if len(order) != 0 {
+		t.Fatal("queued functions ran before the frame")
+	}
+	h.Step(1)
+	if len(order) != 3 {
+		t.Fatalf("%d of 3 queued functions ran", len(order))
+	}
+
+	RunOnMainThread(func() { panic("job panic") })
+	ran := false
+	RunOnMainThread(func() { ran = true })
+	h.Step(1)
+	if !ran {
+		t.Error("a panic stopped the next queued function")
+	}
+}
+
+func TestFutureAwait(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	g := h.Scene().(*coroutineScene).owner
+
+	release := make(chan bool)
+	task := StartTask(func() (interface{}, error) {
+		<-release
+		return 42, nil
+	})
+	var awaited interface{}
+	g.StartCoroutine(func(co *Coroutine) interface{} {
+		awaited, _ = co.Await(task)
+		return nil
+	})
+	var then error
+	failed := NewFuture()
+	failed.Then(func(value interface{}, err error) {
+		then = err
+	})
+
+	h.Step(2)
+	if awaited != nil || task.Done() {
+		t.Fatal("the task completed before it was released")
+	}
+	close(release)
+	if v, err := task.Wait(); v != 42 || err != nil {
+		t.Fatalf("task result is %v, %v", v, err)
+	}
+	failed.Fail(errors.New("failed"))
+	failed.Resolve(1)
+	h.Step(1)
+	if awaited != 42 {
+		t.Errorf("Await returned %v", awaited)
+	}
+	if then == nil {
+		t.Error("Then wasn't called on the main thread")
+	}
+	if v, _ := failed.Result(); v != nil {
+		t.Error("a future can only complete once")
+	}
+}
@@ -15,6 +15,9 @@ type BasicMaterial struct {
 	Program        gl.Program
 	vertexShader   string
 	fragmentShader string
+	//vertexPath and fragmentPath are the files of the shaders, they are reloaded when hot reload is enabled.
+	vertexPath   string
+	fragmentPath string
 
 	ViewMatrix, ProjMatrix, ModelMatrix, AddColor, Texture, Tiling, Offset gl.UniformLocation
 	Verts, UV                                                              gl.AttribLocation
@@ -24,10 +27,65 @@ func NewBasicMaterial(vertexShader, fragmentShader string) *BasicMaterial {
 	return &BasicMaterial{Program: gl.CreateProgram(), vertexShader: vertexShader, fragmentShader: fragmentShader}
 }
 
+// LoadBasicMaterial creates a material from shader files, the files are watched when hot reload is enabled
+// even if they don't compile yet, so they can be fixed while the game runs.
+func LoadBasicMaterial(vertexPath, fragmentPath string) (*BasicMaterial, error) {
+	b := &BasicMaterial{Program: gl.CreateProgram(), vertexPath: vertexPath, fragmentPath: fragmentPath}
+	watchFile(vertexPath, b, b.Reload)
+	watchFile(fragmentPath, b, b.Reload)
+	if err := b.readSources(); err != nil {
+		return b, err
+	}
+	return b, b.Load()
+}
+
+func (b *BasicMaterial) readSources() error {
+	vrt, err := Files.ReadFile(b.vertexPath)
+	if err != nil {
+		return err
+	}
+	frg, err := Files.ReadFile(b.fragmentPath)
+	if err != nil {
+		return err
+	}
+	b.vertexShader, b.fragmentShader = string(vrt), string(frg)
+	return nil
+}
+
+// Reload reads the shader files again and links a new program, if it fails the old program is kept.
+func (b *BasicMaterial) Reload() error {
+	if b.vertexPath == "" || b.fragmentPath == "" {
+		return fmt.Errorf("material was not loaded from files")
+	}
+	old := *b
+	if err := b.readSources(); err != nil {
+		return err
+	}
+	b.Program = gl.CreateProgram()
+	if err := b.Load(); err != nil {
+		b.Program.Delete()
+		*b = old
+		return err
+	}
+	old.Program.Delete()
+	return nil
+}
+
+func (b *BasicMaterial) Release() {
+	unwatchFiles(b)
+	if b.Program != 0 {
+		b.Program.Delete()
+		b.Program = 0
+	}
+}
+
 func (b *BasicMaterial) Load() error {
 	program := b.Program
 	vrt := gl.CreateShader(gl.VERTEX_SHADER)
 	frg := gl.CreateShader(gl.FRAGMENT_SHADER)
+	//Attached shaders are deleted with the program.
+	defer vrt.Delete()
+	defer frg.Delete()
 
 	vrt.Source(b.vertexShader)
 	frg.Source(b.fragmentShader)
@@ -48,6 +106,11 @@ func (b *BasicMaterial) Load() error {
 	program.BindAttribLocation(1, "vertexUV")
 
 	program.Link()
+	if program.Get(gl.LINK_STATUS) != 1 {
+		return fmt.Errorf("Error in Linking Program:%s\n", program.GetInfoLog())
+	}
+	//The default uniforms are set on the program in use.
+	program.Use()
 
 	b.Verts = program.GetAttribLocation("vertexPos")
 	b.UV = program.GetAttribLocation("vertexUV")
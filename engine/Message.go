@@ -0,0 +1,192 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Messages call a method by name on every component of a game object that has it, like Unity's SendMessage.
+// Inactive game objects don't receive messages.
+//
+//	func (ds *Destoyable) Update() {
+//		ds.GameObject().SendMessage("OnDie", true)
+//	}
+//
+//	func (ms *Missle) OnDie(byTimer bool) {
+//		...
+//	}
+
+var messageMethods = make(map[reflect.Type]map[string]int)
+
+func messageMethod(typ reflect.Type, name string) (int, bool) {
+	methods, exists := messageMethods[typ]
+	if !exists {
+		methods = make(map[string]int)
+		messageMethods[typ] = methods
+	}
+	index, exists := methods[name]
+	if !exists {
+		index = -1
+		if m, ok := typ.MethodByName(name); ok {
+			index = m.Index
+		}
+		methods[name] = index
+	}
+	return index, index >= 0
+}
+
+// SendMessage calls method on every component of the game object that has it and returns false if none has.
+func (g *GameObject) SendMessage(method string, args ...interface{}) bool {
+	if !g.active || !g.valid {
+		return false
+	}
+	received := false
+	comps := g.components
+	for i := len(comps) - 1; i >= 0; i-- {
+		c := comps[i]
+		index, ok := messageMethod(reflect.TypeOf(c), method)
+		if !ok {
+			continue
+		}
+		m := reflect.ValueOf(c).Method(index)
+		m.Call(messageArgs(c, method, m.Type(), args))
+		received = true
+	}
+	return received
+}
+
+// SendMessageUpwards sends the message to the game object and every parent up to the root.
+func (g *GameObject) SendMessageUpwards(method string, args ...interface{}) bool {
+	received := false
+	for t := g.transform; t != nil; t = t.parent {
+		if t.gameObject.SendMessage(method, args...) {
+			received = true
+		}
+	}
+	return received
+}
+
+// BroadcastMessage sends the message to the game object and all of its children.
+func (g *GameObject) BroadcastMessage(method string, args ...interface{}) bool {
+	if !g.active || !g.valid {
+		return false
+	}
+	received := g.SendMessage(method, args...)
+	for _, c := range g.transform.Children() {
+		if c.gameObject.BroadcastMessage(method, args...) {
+			received = true
+		}
+	}
+	return received
+}
+
+func messageArgs(c Component, method string, typ reflect.Type, args []interface{}) []reflect.Value {
+	if typ.NumIn() != len(args) || typ.IsVariadic() {
+		panic(fmt.Sprintf("%v.%s takes %d arguments, message has %d", reflect.TypeOf(c), method, typ.NumIn(), len(args)))
+	}
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		pt := typ.In(i)
+		if arg == nil {
+			in[i] = reflect.Zero(pt)
+			continue
+		}
+		v := reflect.ValueOf(arg)
+		if !v.Type().AssignableTo(pt) {
+			panic(fmt.Sprintf("%v.%s argument %d is %v, message has %v", reflect.TypeOf(c), method, i, pt, v.Type()))
+		}
+		in[i] = v
+	}
+	return in
+}
+
+// Subscription is a handler of a scene event, it is removed when its owner is destroyed or when Unsubscribe is called.
+type Subscription struct {
+	bus     *EventBus
+	typ     reflect.Type
+	owner   *GameObject
+	handler interface{}
+}
+
+// EventBus is a typed publish/subscribe bus, every scene has one (see SceneData.Events).
+type EventBus struct {
+	handlers map[reflect.Type][]*Subscription
+}
+
+func NewEventBus() *EventBus {
+	return &EventBus{handlers: make(map[reflect.Type][]*Subscription)}
+}
+
+// Subscribe calls handler for every event of type T published on the scene bus until owner is destroyed,
+// events are skipped while owner is inactive. owner can be nil, the subscription then lasts until the scene is unloaded.
+func Subscribe[T any](owner *GameObject, handler func(T)) *Subscription {
+	return SubscribeBus(GetScene().SceneBase().Events(), owner, handler)
+}
+
+// Publish sends event to every subscriber of its type on the scene bus.
+func Publish[T any](event T) {
+	PublishBus(GetScene().SceneBase().Events(), event)
+}
+
+func SubscribeBus[T any](bus *EventBus, owner *GameObject, handler func(T)) *Subscription {
+	s := &Subscription{bus: bus, typ: reflect.TypeOf((*T)(nil)).Elem(), owner: owner, handler: handler}
+	//Copy on write so publishing can range over the old slice.
+	old := bus.handlers[s.typ]
+	subs := make([]*Subscription, len(old), len(old)+1)
+	copy(subs, old)
+	bus.handlers[s.typ] = append(subs, s)
+	if owner != nil {
+		owner.subscriptions = append(owner.subscriptions, s)
+	}
+	return s
+}
+
+func PublishBus[T any](bus *EventBus, event T) {
+	for _, s := range bus.handlers[reflect.TypeOf((*T)(nil)).Elem()] {
+		if s.bus == nil {
+			continue
+		}
+		if s.owner != nil && !s.owner.active {
+			continue
+		}
+		s.handler.(func(T))(event)
+	}
+}
+
+func (s *Subscription) Unsubscribe() {
+	if s.bus == nil {
+		return
+	}
+	old := s.bus.handlers[s.typ]
+	subs := make([]*Subscription, 0, len(old))
+	for _, o := range old {
+		if o != s {
+			subs = append(subs, o)
+		}
+	}
+	s.bus.handlers[s.typ] = subs
+	s.bus = nil
+	if s.owner != nil {
+		for i, o := range s.owner.subscriptions {
+			if o == s {
+				s.owner.subscriptions = append(s.owner.subscriptions[:i], s.owner.subscriptions[i+1:]...)
+				break
+			}
+		}
+		s.owner = nil
+	}
+}
+
+// Events returns the event bus of the scene.
+func (s *SceneData) Events() *EventBus {
+	if s.events == nil {
+		s.events = NewEventBus()
+	}
+	return s.events
+}
+
+func (g *GameObject) unsubscribeAll() {
+	for len(g.subscriptions) > 0 {
+		g.subscriptions[len(g.subscriptions)-1].Unsubscribe()
+	}
+}
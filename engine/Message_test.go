@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"testing"
+)
+
+type receiver struct {
+	BaseComponent
+	hits int
+	last string
+}
+
+func (r *receiver) OnHit(damage int, from string) {
+	r.hits += damage
+	r.last = from
+}
+
+type scoreEvent struct {
+	Points int
+}
+
+func TestMessages(t *testing.T) {
+	root := NewGameObject("Root")
+	child := NewGameObject("Child")
+	child.Transform().SetParent2(root)
+	rr := &receiver{BaseComponent: NewComponent()}
+	cr := &receiver{BaseComponent: NewComponent()}
+	root.AddComponent(rr)
+	child.AddComponent(cr)
+
+	if !child.SendMessage("OnHit", 1, "a") || cr.hits != 1 || rr.hits != 0 {
+		t.Errorf("SendMessage reached %d/%d, expected 1/0", cr.hits, rr.hits)
+	}
+	child.SendMessageUpwards("OnHit", 1, "b")
+	if cr.hits != 2 || rr.hits != 1 || rr.last != "b" {
+		t.Errorf("SendMessageUpwards reached %d/%d, expected 2/1", cr.hits, rr.hits)
+	}
+	root.BroadcastMessage("OnHit", 1, "c")
+	if cr.hits != 3 || rr.hits != 2 {
+		t.Errorf("BroadcastMessage reached %d/%d, expected 3/2", cr.hits, rr.hits)
+	}
+	if root.SendMessage("Missing") {
+		t.Error("SendMessage found a receiver for a missing method")
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	bus := NewEventBus()
+	owner := NewGameObject("Owner")
+	total := 0
+	SubscribeBus(bus, owner, func(e scoreEvent) { total += e.Points })
+	other := SubscribeBus(bus, nil, func(e scoreEvent) { total += e.Points * 10 })
+
+	PublishBus(bus, scoreEvent{1})
+	if total != 11 {
+		t.Errorf("total is %d, expected 11", total)
+	}
+
+	other.Unsubscribe()
+	owner.Destroy()
+	owner.destroy()
+	PublishBus(bus, scoreEvent{1})
+	if total != 11 {
+		t.Errorf("total is %d after unsubscribing, expected 11", total)
+	}
+}
@@ -14,7 +14,7 @@ func NewMouse() *Mouse {
 }
 
 func (m *Mouse) OnComponentBind(gameObject *GameObject) {
-	gameObject.Tag = MouseTag
+	gameObject.SetTag(MouseTag)
 	gameObject.AddComponent(NewPhysics2(false, chipmunk.NewCircle(vect.Vect{0, 0}, 0.5)))
 	ph := gameObject.Physics
 	ph.Body.SetMass(Inf)
@@ -24,7 +24,9 @@ func (m *Mouse) OnComponentBind(gameObject *GameObject) {
 }
 
 func (m *Mouse) Update() {
-	m.Transform().SetPosition(mainScene.SceneBase().Camera.MouseLocalPosition())
+	if camera := currentCamera(); camera != nil {
+		m.Transform().SetPosition(camera.MouseLocalPosition())
+	}
 }
 
 func (m *Mouse) Start() {
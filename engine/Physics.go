@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"encoding/json"
 	"github.com/vova616/chipmunk"
 	"github.com/vova616/chipmunk/vect"
 )
@@ -14,6 +15,9 @@ type Physics struct {
 	lastPosition vect.Vect
 	lastAngle    vect.Float
 	Interpolate  bool
+
+	//The body is removed from the Space while the component or its game object is disabled.
+	inSpace bool
 }
 
 func NewPhysics(static bool, w, h float32) *Physics {
@@ -69,6 +73,10 @@ func NewPhysicsShapes(static bool, shapes []*chipmunk.Shape) *Physics {
 
 func (p *Physics) Start() {
 	//p.Interpolate = true
+	p.addBody()
+}
+
+func (p *Physics) addBody() {
 	pos := p.GameObject().Transform().WorldPosition()
 	p.Body.SetAngle(vect.Float(p.GameObject().Transform().WorldRotation().Z) * RadianConst)
 	p.Body.SetPosition(vect.Vect{vect.Float(pos.X), vect.Float(pos.Y)})
@@ -79,9 +87,66 @@ func (p *Physics) Start() {
 	}
 
 	//p.Body.UpdateShapes()
+	p.inSpace = true
+	for i, b := range removedBodies {
+		if b == p.Body {
+			removedBodies = append(removedBodies[:i], removedBodies[i+1:]...)
+			return
+		}
+	}
 	Space.AddBody(p.Body)
 }
 
+func (p *Physics) removeBody() {
+	if !p.inSpace {
+		return
+	}
+	p.inSpace = false
+	if stepping {
+		removedBodies = append(removedBodies, p.Body)
+		return
+	}
+	Space.RemoveBody(p.Body)
+}
+
+// Bodies can't leave the Space while it steps (objects are disabled or despawned from collision callbacks),
+// they are removed after the step.
+var (
+	stepping      bool
+	removedBodies []*chipmunk.Body
+)
+
+func stepSpace(dt vect.Float) {
+	stepping = true
+	Space.Step(dt)
+	stepping = false
+	for i, b := range removedBodies {
+		Space.RemoveBody(b)
+		removedBodies[i] = nil
+	}
+	removedBodies = removedBodies[:0]
+}
+
+// OnSpawn stops the body when its pooled game object is spawned again.
+func (p *Physics) OnSpawn() {
+	if p.Body.IsStatic() {
+		return
+	}
+	p.Body.SetVelocity(0, 0)
+	p.Body.SetAngularVelocity(0)
+	p.Body.SetForce(0, 0)
+}
+
+func (p *Physics) OnEnable() {
+	if p.started() && !p.inSpace {
+		p.addBody()
+	}
+}
+
+func (p *Physics) OnDisable() {
+	p.removeBody()
+}
+
 func (p *Physics) OnComponentBind(gobj *GameObject) {
 	gobj.Physics = p
 	//p.Body.UserData = &gobj.name
@@ -118,10 +183,11 @@ func (p *Physics) CollisionPostSolve(arbiter *chipmunk.Arbiter) {
 
 func (p *Physics) OnDestroy() {
 	p.gameObject = nil
-	Space.RemoveBody(p.Body)
+	p.removeBody()
 }
 
 func (p *Physics) Clone() {
+	p.inSpace = false
 	p.Body = p.Body.Clone()
 	p.Box = p.Body.Shapes[0].GetAsBox()
 	p.Shape = p.Body.Shapes[0]
@@ -129,3 +195,58 @@ func (p *Physics) Clone() {
 	//p.Body.UpdateShapes()
 	//p.GameObject().Physics = nil
 }
+
+type physicsData struct {
+	Static      bool
+	Circle      bool    `json:",omitempty"`
+	Radius      float32 `json:",omitempty"`
+	Width       float32 `json:",omitempty"`
+	Height      float32 `json:",omitempty"`
+	Mass        float32 `json:",omitempty"`
+	IsSensor    bool    `json:",omitempty"`
+	Interpolate bool    `json:",omitempty"`
+}
+
+// MarshalJSON saves the body type and its first shape for scene files.
+func (p *Physics) MarshalJSON() ([]byte, error) {
+	d := physicsData{Static: p.Body.IsStatic(), Interpolate: p.Interpolate}
+	if !d.Static {
+		d.Mass = float32(p.Body.Mass())
+	}
+	if p.Shape != nil {
+		d.IsSensor = p.Shape.IsSensor
+		if cir := p.Shape.GetAsCircle(); cir != nil {
+			d.Circle = true
+			d.Radius = float32(cir.Radius)
+		} else if p.Box != nil {
+			d.Width, d.Height = float32(p.Box.Width), float32(p.Box.Height)
+		}
+	}
+	return json.Marshal(&d)
+}
+
+// UnmarshalJSON replaces the body with the one saved by MarshalJSON.
+func (p *Physics) UnmarshalJSON(data []byte) error {
+	d := physicsData{Width: 1, Height: 1, Mass: 1}
+	if err := json.Unmarshal(data, &d); err != nil {
+		return err
+	}
+
+	var np *Physics
+	if d.Circle {
+		np = NewPhysics2(d.Static, chipmunk.NewCircle(vect.Vect{0, 0}, d.Radius))
+	} else {
+		np = NewPhysics(d.Static, d.Width, d.Height)
+	}
+	if !d.Static {
+		np.Body.SetMass(vect.Float(d.Mass))
+	}
+	np.Shape.IsSensor = d.IsSensor
+
+	p.Body, p.Box, p.Shape = np.Body, np.Box, np.Shape
+	p.Interpolate = d.Interpolate
+	if p.gameObject != nil {
+		p.Body.CallbackHandler = p
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package engine
+
+// Pools keep instances of a prefab for objects that are spawned all the time like missiles,
+// Despawn deactivates an instance and keeps it for the next Spawn instead of destroying it.
+//
+//	missles := engine.NewPool(prefab, 32)
+//	m := missles.Spawn(position, GameSceneGeneral.Layer3)
+//	...
+//	engine.Despawn(m) //Destroys objects that were not spawned by a pool.
+//
+// Instances are never started again, components reset their state in OnSpawn and OnDespawn.
+// The physics body of a pooled instance leaves the Space while it waits and is stopped when it is spawned,
+// waiting instances are not found by FindWithTag and the other Find functions of the indexes.
+
+// OnSpawner is implemented by components that reset their state when their pooled game object is spawned.
+type OnSpawner interface {
+	OnSpawn()
+}
+
+// OnDespawner is implemented by components that clean up when their game object returns to its pool.
+type OnDespawner interface {
+	OnDespawn()
+}
+
+type Pool struct {
+	prefab *Prefab
+	free   []*GameObject
+
+	//Max is the number of instances the pool keeps, instances that are despawned over it are destroyed. 0 is unlimited.
+	Max int
+
+	destroyed bool
+}
+
+// NewPool creates a pool of the prefab with prewarm instances waiting in the current scene.
+func NewPool(prefab *Prefab, prewarm int) *Pool {
+	p := &Pool{prefab: prefab}
+	p.Prewarm(prewarm)
+	return p
+}
+
+func (p *Pool) Prefab() *Prefab {
+	return p.prefab
+}
+
+// Free returns the number of instances waiting to be spawned.
+func (p *Pool) Free() int {
+	return len(p.free)
+}
+
+// Prewarm creates n inactive instances in the current scene.
+func (p *Pool) Prewarm(n int) {
+	for i := 0; i < n; i++ {
+		g := p.create()
+		g.SetActive(false)
+		g.inPool = true
+		GetScene().SceneBase().AddGameObject(g)
+		p.free = append(p.free, g)
+	}
+}
+
+func (p *Pool) create() *GameObject {
+	g := p.prefab.template.Clone()
+	g.pool = p
+	return g
+}
+
+// Spawn activates a waiting instance (or creates one if there is none) at position under parent,
+// the instance is a root object of the current scene if parent is nil.
+func (p *Pool) Spawn(position Vector, parent *GameObject) *GameObject {
+	var g *GameObject
+	for g == nil && len(p.free) > 0 {
+		last := len(p.free) - 1
+		g = p.free[last]
+		p.free[last] = nil
+		p.free = p.free[:last]
+		//Instances are destroyed with their scene.
+		if !g.valid || g.destoryMark {
+			g = nil
+		}
+	}
+	if g == nil {
+		g = p.create()
+		g.SetActive(false)
+	}
+
+	if parent != nil {
+		if g.scene != nil {
+			g.scene.RemoveGameObject(g)
+		}
+		if g.transform.parent != parent.transform {
+			g.transform.SetParent2(parent)
+		}
+	} else if g.scene == nil {
+		g.transform.SetParent(nil)
+		GetScene().SceneBase().AddGameObject(g)
+	}
+
+	t := p.prefab.template.transform
+	g.transform.SetRotation(t.Rotation())
+	g.transform.SetScale(t.Scale())
+	g.transform.SetWorldPosition(position)
+
+	g.inPool = false
+	reindex(g)
+	IterAll([]*GameObject{g}, func(o *GameObject) {
+		for _, c := range o.components {
+			if s, ok := c.(OnSpawner); ok {
+				s.OnSpawn()
+			}
+		}
+	})
+	g.SetActive(true)
+	return g
+}
+
+// Release deactivates a spawned instance and keeps it for the next Spawn.
+func (p *Pool) Release(g *GameObject) {
+	if g.pool != p || g.inPool || g.destoryMark || !g.valid {
+		return
+	}
+	IterAll([]*GameObject{g}, func(o *GameObject) {
+		for _, c := range o.components {
+			if d, ok := c.(OnDespawner); ok {
+				d.OnDespawn()
+			}
+		}
+	})
+	g.SetActive(false)
+	if p.destroyed || (p.Max > 0 && len(p.free) >= p.Max) {
+		g.Destroy()
+		return
+	}
+	g.inPool = true
+	reindex(g)
+	p.free = append(p.free, g)
+}
+
+// Destroy destroys the waiting instances, the instances that are still spawned are destroyed when they are despawned.
+// Use it when the pool is replaced by a pool of another prefab.
+func (p *Pool) Destroy() {
+	p.destroyed = true
+	for _, g := range p.free {
+		if g.valid && !g.destoryMark {
+			g.Destroy()
+		}
+	}
+	p.free = nil
+}
+
+// Pool returns the pool that created the game object, nil if it was not created by a pool.
+func (g *GameObject) Pool() *Pool {
+	return g.pool
+}
+
+// Despawn returns the game object to its pool, game objects without a pool are destroyed.
+func Despawn(g *GameObject) {
+	if g.pool == nil {
+		g.Destroy()
+		return
+	}
+	g.pool.Release(g)
+}
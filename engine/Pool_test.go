@@ -0,0 +1,109 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+type spawnCounter struct {
+	BaseComponent
+	starts, spawns, despawns int
+}
+
+func (c *spawnCounter) Start() {
+	c.starts++
+}
+
+func (c *spawnCounter) OnSpawn() {
+	c.spawns++
+}
+
+func (c *spawnCounter) OnDespawn() {
+	c.despawns++
+}
+
+type poolScene struct {
+	*SceneData
+	pool *Pool
+}
+
+func (s *poolScene) New() Scene {
+	return &poolScene{SceneData: NewScene("PoolScene")}
+}
+
+func (s *poolScene) Load() {
+	bullet := NewGameObject("Bullet")
+	bullet.AddComponent(NewPhysics(false, 1, 1))
+	bullet.SetTag("Bullet")
+	bullet.AddComponent(&spawnCounter{BaseComponent: NewComponent()})
+	s.pool = NewPool(NewPrefab(bullet), 2)
+}
+
+func TestPoolReuse(t *testing.T) {
+	h := NewHarness(&poolScene{}, time.Second/60)
+	p := h.Scene().(*poolScene).pool
+	if p.Free() != 2 {
+		t.Fatalf("%d free instances, expected 2", p.Free())
+	}
+
+	if n := len(FindAllWithTag("Bullet")); n != 0 {
+		t.Fatalf("found %d waiting instances by tag", n)
+	}
+
+	g := p.Spawn(Vector{10, 0, 0}, nil)
+	if all := FindAllWithTag("Bullet"); len(all) != 1 || all[0] != g {
+		t.Fatalf("FindAllWithTag returned %v after Spawn", all)
+	}
+	h.Step(1)
+	c, _ := GetComponent[*spawnCounter](g)
+	if !g.Physics.inSpace || c.starts != 1 || c.spawns != 1 {
+		t.Fatalf("body in space %v, started %d times, spawned %d times", g.Physics.inSpace, c.starts, c.spawns)
+	}
+
+	g.Physics.Body.SetVelocity(5, 5)
+	Despawn(g)
+	if FindWithTag("Bullet") != nil {
+		t.Error("a despawned instance was found by tag")
+	}
+	if g.Physics.inSpace || g.ActiveSelf() || p.Free() != 2 || c.despawns != 1 {
+		t.Errorf("body in space %v, active %v, %d free instances", g.Physics.inSpace, g.ActiveSelf(), p.Free())
+	}
+	Despawn(g)
+	if p.Free() != 2 {
+		t.Error("despawning twice should not add the instance twice")
+	}
+
+	if p.Spawn(Vector{}, nil) != g {
+		t.Fatal("the despawned instance should be reused")
+	}
+	h.Step(1)
+	if v := g.Physics.Body.Velocity(); v.X != 0 || v.Y != 0 || !g.Physics.inSpace {
+		t.Errorf("velocity is %v and body in space %v after spawning", v, g.Physics.inSpace)
+	}
+	if c.starts != 1 || c.spawns != 2 {
+		t.Errorf("started %d times, spawned %d times", c.starts, c.spawns)
+	}
+	h.Close()
+}
+
+func TestPoolDestroy(t *testing.T) {
+	h := NewHarness(&poolScene{}, time.Second/60)
+	p := h.Scene().(*poolScene).pool
+	spawned := p.Spawn(Vector{}, nil)
+	waiting := p.free[0]
+
+	p.Destroy()
+	h.Step(1)
+	if waiting.IsValid() || p.Free() != 0 {
+		t.Error("the waiting instances were not destroyed")
+	}
+	if !spawned.IsValid() {
+		t.Fatal("a spawned instance was destroyed before it was despawned")
+	}
+	Despawn(spawned)
+	h.Step(1)
+	if spawned.IsValid() || p.Free() != 0 {
+		t.Error("an instance that was despawned into a destroyed pool was kept")
+	}
+	h.Close()
+}
@@ -0,0 +1,267 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+// Prefab is a game object template, Instantiate makes new game objects from it.
+// The template itself is never added to a scene.
+//
+// Components are copied by these rules:
+//   - Values are copied.
+//   - Exported slices, maps and arrays get their own copy, tag the field with `copy:"shared"` to share it between instances.
+//   - Pointers to game objects and components inside the prefab point to the matching object of the instance.
+//   - Any other pointer (textures, atlases, game objects outside the prefab) is shared.
+//   - Clone() is called on the new component last, for everything else (Physics makes a new body there).
+//
+// Prefab files use the game object format of scene files (see Serialize.go).
+type Prefab struct {
+	name     string
+	template *GameObject
+}
+
+// Override changes an exported field of one component of an instance.
+type Override struct {
+	//Path of the child from the instance root separated by "/", empty for the root itself.
+	Path string
+	//Registered name or type name of the component.
+	Component string
+	//Field name, nested fields are separated by dots (Color.A).
+	Field string
+	Value interface{}
+}
+
+var prefabs = make(map[string]*Prefab)
+
+// NewPrefab makes a prefab from template, the template should not be used in a scene.
+func NewPrefab(template *GameObject) *Prefab {
+	return &Prefab{name: template.name, template: template}
+}
+
+// LoadPrefab loads a prefab file, prefabs are cached by path.
+func LoadPrefab(path string) (*Prefab, error) {
+	if p, exists := prefabs[path]; exists {
+		return p, nil
+	}
+	f, err := Files.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := DecodePrefab(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	prefabs[path] = p
+	return p, nil
+}
+
+func DecodePrefab(r io.Reader) (*Prefab, error) {
+	data := new(GameObjectData)
+	if err := json.NewDecoder(r).Decode(data); err != nil {
+		return nil, err
+	}
+	template, err := DecodeGameObject(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewPrefab(template), nil
+}
+
+func (p *Prefab) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return p.Encode(f)
+}
+
+func (p *Prefab) Encode(w io.Writer) error {
+	data, err := EncodeGameObject(p.template)
+	if err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(data, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func (p *Prefab) Name() string {
+	return p.name
+}
+
+// Template returns the game object instances are copied from, changing it changes every future instance.
+func (p *Prefab) Template() *GameObject {
+	return p.template
+}
+
+// Instantiate copies the prefab to the world position, under parent or at the root of the current scene if parent is nil.
+func Instantiate(prefab *Prefab, position Vector, parent *GameObject, overrides ...Override) *GameObject {
+	g := prefab.template.Clone()
+	for _, o := range overrides {
+		if err := o.Apply(g); err != nil {
+			LogAssets.Warn("Prefab override failed", "prefab", prefab.name, "err", err)
+		}
+	}
+	if parent != nil {
+		g.Transform().SetParent2(parent)
+	} else {
+		GetScene().SceneBase().AddGameObject(g)
+	}
+	g.Transform().SetWorldPosition(position)
+	return g
+}
+
+func (o Override) Apply(g *GameObject) error {
+	target := g
+	if o.Path != "" {
+		t := g.transform.Find(o.Path)
+		if t == nil {
+			return fmt.Errorf("%s has no child %s", g.name, o.Path)
+		}
+		target = t.gameObject
+	}
+
+	var c Component
+	for _, tc := range target.components {
+		name, _ := ComponentName(tc)
+		if name == o.Component || reflect.TypeOf(tc).Elem().Name() == o.Component {
+			c = tc
+			break
+		}
+	}
+	if c == nil {
+		return fmt.Errorf("%s has no component %s", target.name, o.Component)
+	}
+
+	return SetField(c, o.Field, o.Value)
+}
+
+func setValue(v reflect.Value, value interface{}) error {
+	nv := reflect.ValueOf(value)
+	if !nv.IsValid() {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if nv.Type().AssignableTo(v.Type()) {
+		v.Set(nv)
+		return nil
+	}
+	if isNumber(nv.Kind()) && isNumber(v.Kind()) {
+		v.Set(nv.Convert(v.Type()))
+		return nil
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v.Addr().Interface())
+}
+
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
+type cloneRefs map[interface{}]interface{}
+
+func copyComponent(c Component) Component {
+	v := reflect.ValueOf(c).Elem()
+	n := reflect.New(v.Type()).Elem()
+	n.Set(v)
+	copyFields(n)
+	return n.Addr().Interface().(Component)
+}
+
+// copyFields gives the exported slices and maps of a struct their own copy.
+func copyFields(v reflect.Value) {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("copy") == "shared" {
+			continue
+		}
+		fv := v.Field(i)
+		fv.Set(deepCopy(fv))
+	}
+}
+
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			n.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return n
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		n := reflect.MakeMap(v.Type())
+		for _, k := range v.MapKeys() {
+			n.SetMapIndex(k, deepCopy(v.MapIndex(k)))
+		}
+		return n
+	case reflect.Array:
+		n := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			n.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return n
+	case reflect.Struct:
+		n := reflect.New(v.Type()).Elem()
+		n.Set(v)
+		copyFields(n)
+		return n
+	}
+	return v
+}
+
+// remapFields points the exported fields of a copied struct at the copies of the objects they referenced.
+func remapFields(v reflect.Value, refs cloneRefs) {
+	typ := v.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Tag.Get("copy") == "shared" {
+			continue
+		}
+		remapValue(v.Field(i), refs)
+	}
+}
+
+func remapValue(v reflect.Value, refs cloneRefs) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() || v.Elem().Kind() != reflect.Ptr {
+			return
+		}
+		fallthrough
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if n, exists := refs[v.Interface()]; exists {
+			nv := reflect.ValueOf(n)
+			if nv.Type().AssignableTo(v.Type()) {
+				v.Set(nv)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			remapValue(v.Index(i), refs)
+		}
+	case reflect.Struct:
+		remapFields(v, refs)
+	}
+}
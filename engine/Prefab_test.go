@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"testing"
+)
+
+type turret struct {
+	BaseComponent
+	Barrel  *turret
+	Offsets []Vector
+	Shared  []int `copy:"shared"`
+	Damage  float32
+}
+
+func newTurretPrefab() *Prefab {
+	root := NewGameObject("Turret")
+	rt := &turret{BaseComponent: NewComponent(), Offsets: []Vector{{1, 2, 0}}, Shared: []int{1}, Damage: 10}
+	root.AddComponent(rt)
+
+	barrel := NewGameObject("Barrel")
+	bt := &turret{BaseComponent: NewComponent(), Damage: 5}
+	barrel.AddComponent(bt)
+	barrel.Transform().SetParent2(root)
+	rt.Barrel = bt
+
+	return NewPrefab(root)
+}
+
+func TestPrefabCopyRules(t *testing.T) {
+	p := newTurretPrefab()
+	g := p.Template().Clone()
+
+	src := p.Template().components[0].(*turret)
+	dst := g.components[0].(*turret)
+
+	dst.Offsets[0].X = 100
+	if src.Offsets[0].X != 1 {
+		t.Error("slices should be copied")
+	}
+	dst.Shared[0] = 100
+	if src.Shared[0] != 100 {
+		t.Error(`slices tagged copy:"shared" should be shared`)
+	}
+	bt := g.Transform().Find("Barrel")
+	if bt == nil {
+		t.Fatal("child was not copied")
+	}
+	barrel := bt.GameObject()
+	if dst.Barrel != barrel.components[0] {
+		t.Error("references inside the prefab should point to the copy")
+	}
+	if dst.GameObject() != g || dst.Barrel.GameObject() != barrel {
+		t.Error("copied components are bound to the wrong game object")
+	}
+}
+
+func TestPrefabOverrides(t *testing.T) {
+	p := newTurretPrefab()
+	g := p.Template().Clone()
+
+	overrides := []Override{
+		{Component: "turret", Field: "Damage", Value: 20},
+		{Path: "Barrel", Component: "turret", Field: "Offsets", Value: []map[string]float32{{"X": 3}}},
+	}
+	for _, o := range overrides {
+		if err := o.Apply(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := g.components[0].(*turret).Damage; d != 20 {
+		t.Errorf("Damage is %f, expected 20", d)
+	}
+	if o := g.Transform().Find("Barrel").GameObject().components[0].(*turret).Offsets; len(o) != 1 || o[0].X != 3 {
+		t.Errorf("Offsets is %v, expected [{3 0 0}]", o)
+	}
+	if d := p.Template().components[0].(*turret).Damage; d != 10 {
+		t.Error("overrides changed the template")
+	}
+
+	if err := (Override{Component: "turret", Field: "Missing", Value: 1}).Apply(g); err == nil {
+		t.Error("expected an error for a missing field")
+	}
+}
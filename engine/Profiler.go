@@ -0,0 +1,298 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The profiler records named and nested scopes of every frame, Run records its phases and components can add their own:
+//
+//	func (ai *EnemeyAI) Update() {
+//		defer engine.ProfileScope("EnemeyAI")()
+//		...
+//	}
+//
+// DefaultProfiler keeps the last frames with their counters (draw calls, game objects, physics bodies, coroutines),
+// SaveTrace writes them in the Chrome trace_event format, open the file in chrome://tracing.
+
+// ProfileSample is a scope of a frame, Start is relative to the start of the frame.
+type ProfileSample struct {
+	Name     string
+	Depth    int
+	Start    time.Duration
+	Duration time.Duration
+}
+
+type ProfileFrame struct {
+	Index    int
+	Start    time.Time
+	Duration time.Duration
+	Samples  []ProfileSample
+	Counters map[string]int64
+}
+
+// Time returns the total duration of the scopes with the name.
+func (f *ProfileFrame) Time(name string) time.Duration {
+	var d time.Duration
+	for i := range f.Samples {
+		if f.Samples[i].Name == name {
+			d += f.Samples[i].Duration
+		}
+	}
+	return d
+}
+
+// CounterNames returns the names of the frame counters sorted.
+func (f *ProfileFrame) CounterNames() []string {
+	names := make([]string, 0, len(f.Counters))
+	for name := range f.Counters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Print writes the scopes and counters of the frame, scopes are indented by their depth.
+func (f *ProfileFrame) Print(w io.Writer) {
+	fmt.Fprintf(w, "Frame %d %v\n", f.Index, f.Duration)
+	for _, s := range f.Samples {
+		fmt.Fprintf(w, "%s%s %v\n", strings.Repeat("  ", s.Depth+1), s.Name, s.Duration)
+	}
+	for _, name := range f.CounterNames() {
+		fmt.Fprintf(w, "  %s: %d\n", name, f.Counters[name])
+	}
+}
+
+// Profiler keeps a ring buffer of the last frames.
+type Profiler struct {
+	Enabled bool
+
+	frames    []ProfileFrame
+	next      int
+	count     int
+	index     int
+	recording bool
+	open      []int
+}
+
+var DefaultProfiler = NewProfiler(300)
+
+func NewProfiler(frames int) *Profiler {
+	if frames < 1 {
+		frames = 1
+	}
+	return &Profiler{Enabled: true, frames: make([]ProfileFrame, frames)}
+}
+
+func (p *Profiler) current() *ProfileFrame {
+	return &p.frames[p.next]
+}
+
+// BeginFrame starts recording a frame, the oldest frame is overwritten when the buffer is full.
+func (p *Profiler) BeginFrame() {
+	if !p.Enabled {
+		return
+	}
+	f := p.current()
+	f.Index = p.index
+	f.Start = time.Now()
+	f.Duration = 0
+	f.Samples = f.Samples[:0]
+	if f.Counters == nil {
+		f.Counters = make(map[string]int64)
+	}
+	for name := range f.Counters {
+		delete(f.Counters, name)
+	}
+	p.open = p.open[:0]
+	p.recording = true
+}
+
+// EndFrame closes the scopes that are still open and stores the frame.
+func (p *Profiler) EndFrame() {
+	if !p.recording {
+		return
+	}
+	for len(p.open) > 0 {
+		p.End()
+	}
+	f := p.current()
+	f.Duration = time.Since(f.Start)
+	p.next = (p.next + 1) % len(p.frames)
+	if p.count < len(p.frames) {
+		p.count++
+	}
+	p.index++
+	p.recording = false
+}
+
+// Begin opens a scope inside the last open scope.
+func (p *Profiler) Begin(name string) {
+	if !p.recording {
+		return
+	}
+	f := p.current()
+	p.open = append(p.open, len(f.Samples))
+	f.Samples = append(f.Samples, ProfileSample{Name: name, Depth: len(p.open) - 1, Start: time.Since(f.Start)})
+}
+
+// End closes the last open scope.
+func (p *Profiler) End() {
+	if !p.recording || len(p.open) == 0 {
+		return
+	}
+	f := p.current()
+	s := &f.Samples[p.open[len(p.open)-1]]
+	p.open = p.open[:len(p.open)-1]
+	s.Duration = time.Since(f.Start) - s.Start
+}
+
+// Scope opens a scope and returns the function that closes it.
+func (p *Profiler) Scope(name string) func() {
+	p.Begin(name)
+	return p.End
+}
+
+func (p *Profiler) SetCounter(name string, value int64) {
+	if p.recording {
+		p.current().Counters[name] = value
+	}
+}
+
+func (p *Profiler) AddCounter(name string, delta int64) {
+	if p.recording {
+		p.current().Counters[name] += delta
+	}
+}
+
+// Frames returns the recorded frames from the oldest to the newest.
+func (p *Profiler) Frames() []*ProfileFrame {
+	frames := make([]*ProfileFrame, 0, p.count)
+	first := p.next - p.count
+	if first < 0 {
+		first += len(p.frames)
+	}
+	for i := 0; i < p.count; i++ {
+		frames = append(frames, &p.frames[(first+i)%len(p.frames)])
+	}
+	return frames
+}
+
+// LastFrame returns the newest recorded frame, nil if there is none.
+func (p *Profiler) LastFrame() *ProfileFrame {
+	if p.count == 0 {
+		return nil
+	}
+	last := p.next - 1
+	if last < 0 {
+		last += len(p.frames)
+	}
+	return &p.frames[last]
+}
+
+// Clear removes the recorded frames.
+func (p *Profiler) Clear() {
+	p.count = 0
+}
+
+type traceEvent struct {
+	Name string           `json:"name"`
+	Ph   string           `json:"ph"`
+	Ts   float64          `json:"ts"`
+	Dur  float64          `json:"dur,omitempty"`
+	Pid  int              `json:"pid"`
+	Tid  int              `json:"tid"`
+	Args map[string]int64 `json:"args,omitempty"`
+}
+
+func microseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Microsecond)
+}
+
+// WriteTrace writes the recorded frames in the Chrome trace_event JSON format.
+func (p *Profiler) WriteTrace(w io.Writer) error {
+	frames := p.Frames()
+	events := make([]traceEvent, 0, len(frames)*16)
+	if len(frames) > 0 {
+		origin := frames[0].Start
+		for _, f := range frames {
+			start := f.Start.Sub(origin)
+			events = append(events, traceEvent{Name: fmt.Sprintf("Frame %d", f.Index), Ph: "X", Ts: microseconds(start), Dur: microseconds(f.Duration), Pid: 1, Tid: 1})
+			for _, s := range f.Samples {
+				events = append(events, traceEvent{Name: s.Name, Ph: "X", Ts: microseconds(start + s.Start), Dur: microseconds(s.Duration), Pid: 1, Tid: 1})
+			}
+			for _, name := range f.CounterNames() {
+				events = append(events, traceEvent{Name: name, Ph: "C", Ts: microseconds(start), Pid: 1, Tid: 1, Args: map[string]int64{"value": f.Counters[name]}})
+			}
+		}
+	}
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents     []traceEvent `json:"traceEvents"`
+		DisplayTimeUnit string       `json:"displayTimeUnit"`
+	}{events, "ms"})
+}
+
+func (p *Profiler) SaveTrace(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteTrace(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ProfileBegin opens a scope on DefaultProfiler.
+func ProfileBegin(name string) {
+	DefaultProfiler.Begin(name)
+}
+
+// ProfileEnd closes the last scope of DefaultProfiler.
+func ProfileEnd() {
+	DefaultProfiler.End()
+}
+
+// ProfileScope opens a scope on DefaultProfiler and returns the function that closes it.
+func ProfileScope(name string) func() {
+	return DefaultProfiler.Scope(name)
+}
+
+var drawCalls int64
+
+// CountDrawCall adds a draw call to the frame counters, components that draw on their own should call it.
+func CountDrawCall() {
+	drawCalls++
+}
+
+// recordCounters sets the engine counters of the frame.
+func recordCounters() {
+	if !DefaultProfiler.recording {
+		drawCalls = 0
+		return
+	}
+	objects, bodies := 0, 0
+	iterAllScenes(func(g *GameObject) {
+		objects++
+		if g.Physics != nil && g.Physics.inSpace {
+			bodies++
+		}
+	})
+	running := 0
+	for _, c := range coroutines {
+		if c != nil {
+			running++
+		}
+	}
+	DefaultProfiler.SetCounter("DrawCalls", drawCalls)
+	DefaultProfiler.SetCounter("GameObjects", int64(objects))
+	DefaultProfiler.SetCounter("PhysicsBodies", int64(bodies))
+	DefaultProfiler.SetCounter("Coroutines", int64(running))
+	drawCalls = 0
+}
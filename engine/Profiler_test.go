@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestProfilerScopes(t *testing.T) {
+	p := NewProfiler(2)
+	for i := 0; i < 3; i++ {
+		p.BeginFrame()
+		p.Begin("Update")
+		p.Scope("AI")()
+		p.End()
+		p.Begin("Unclosed")
+		p.AddCounter("DrawCalls", 2)
+		p.AddCounter("DrawCalls", 3)
+		p.EndFrame()
+	}
+
+	frames := p.Frames()
+	if len(frames) != 2 || frames[0].Index != 1 || frames[1].Index != 2 {
+		t.Fatalf("expected frames 1 and 2 in the ring buffer, got %d frames", len(frames))
+	}
+	f := p.LastFrame()
+	if len(f.Samples) != 3 {
+		t.Fatalf("%d samples, expected 3", len(f.Samples))
+	}
+	if ai := f.Samples[1]; ai.Name != "AI" || ai.Depth != 1 {
+		t.Errorf("AI sample is %+v, expected a child of Update", ai)
+	}
+	if f.Counters["DrawCalls"] != 5 {
+		t.Errorf("DrawCalls is %d, expected 5", f.Counters["DrawCalls"])
+	}
+
+	var buf bytes.Buffer
+	if err := p.WriteTrace(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &trace); err != nil {
+		t.Fatal(err)
+	}
+	//Every frame has a frame event, 3 scopes and a counter.
+	if len(trace.TraceEvents) != 10 {
+		t.Errorf("%d trace events, expected 10", len(trace.TraceEvents))
+	}
+}
@@ -0,0 +1,273 @@
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ComponentFactory creates a new component with default values.
+type ComponentFactory func() Component
+
+// ComponentInfo describes a registered component type, serializers, editors and debug tools use it
+// to create components by name and to read and write their fields.
+type ComponentInfo struct {
+	Name    string
+	Type    reflect.Type
+	Factory ComponentFactory
+	Fields  []*FieldInfo
+
+	//Names of components that are added to the game object before this one if it has none of them.
+	Requires []string
+}
+
+// FieldInfo describes an exported field of a component.
+// Ranges come from a `range:"min,max"` tag or from ComponentInfo.Range.
+type FieldInfo struct {
+	Name  string
+	Type  reflect.Type
+	Index []int
+
+	HasRange bool
+	Min, Max float64
+
+	//Saved in scene and prefab files.
+	Serialized bool
+}
+
+var (
+	componentInfos = make(map[string]*ComponentInfo)
+	componentTypes = make(map[reflect.Type]*ComponentInfo)
+)
+
+func init() {
+	RegisterComponent("Camera", func() Component { return NewCamera() })
+	RegisterComponent("FPS", func() Component { return NewFPS() })
+	RegisterComponent("Mouse", func() Component { return NewMouse() })
+	RegisterComponent("Physics", func() Component { return NewPhysics(false, 1, 1) })
+	RegisterComponent("Sprite", func() Component { return NewSprite3(nil, AnimatedUV{NewUV(0, 0, 1, 1, 1)}) })
+	RegisterComponent("LoadingBar", func() Component { return NewLoadingBar(0, 0) })
+	RegisterComponent("BehaviorTree", func() Component { return NewBehaviorTree(nil) })
+	RegisterComponent("Steering", func() Component { return NewSteering(200, 0) }).Require("Physics")
+	RegisterComponent("Seek", func() Component { return NewSeek(nil, 1) }).Require("Steering")
+	RegisterComponent("Flee", func() Component { return NewFlee(nil, 0, 1) }).Require("Steering")
+	RegisterComponent("Arrive", func() Component { return NewArrive(nil, 100, 1) }).Require("Steering")
+	RegisterComponent("Wander", func() Component { return NewWander(50, 100, 3, 1) }).Require("Steering")
+	RegisterComponent("Pursue", func() Component { return NewPursue(nil, 1) }).Require("Steering")
+	RegisterComponent("Evade", func() Component { return NewEvade(nil, 0, 1) }).Require("Steering")
+	RegisterComponent("Separation", func() Component { return NewSeparation(50, "", 1) }).Require("Steering")
+	RegisterComponent("Alignment", func() Component { return NewAlignment(100, "", 1) }).Require("Steering")
+	RegisterComponent("Cohesion", func() Component { return NewCohesion(100, "", 1) }).Require("Steering")
+	RegisterComponent("ObstacleAvoidance", func() Component { return NewObstacleAvoidance(150, 20, "", 1) }).Require("Steering")
+}
+
+// RegisterComponent makes a component type creatable by name (scene files, tools) and collects its fields.
+// Registering the same name twice replaces the old factory.
+func RegisterComponent(name string, factory ComponentFactory) *ComponentInfo {
+	typ := reflect.TypeOf(factory())
+	if old, exists := componentTypes[typ]; exists && old.Name != name {
+		panic(fmt.Sprintf("component type %v is already registered as %s", typ, old.Name))
+	}
+	info := &ComponentInfo{Name: name, Type: typ, Factory: factory, Fields: componentFields(typ.Elem())}
+	componentInfos[name] = info
+	componentTypes[typ] = info
+	return info
+}
+
+func componentFields(typ reflect.Type) []*FieldInfo {
+	fields := make([]*FieldInfo, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Anonymous {
+			continue
+		}
+		fi := &FieldInfo{
+			Name:       f.Name,
+			Type:       f.Type,
+			Index:      f.Index,
+			Serialized: f.Tag.Get("json") != "-" && serializableType(f.Type, 0),
+		}
+		if r := f.Tag.Get("range"); r != "" {
+			parts := strings.Split(r, ",")
+			if len(parts) != 2 {
+				panic(fmt.Sprintf("%v.%s: bad range tag %q", typ, f.Name, r))
+			}
+			min, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+			max, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+			if err1 != nil || err2 != nil {
+				panic(fmt.Sprintf("%v.%s: bad range tag %q", typ, f.Name, r))
+			}
+			fi.HasRange, fi.Min, fi.Max = true, min, max
+		}
+		fields = append(fields, fi)
+	}
+	return fields
+}
+
+// Require adds components that are added automatically with this one.
+func (info *ComponentInfo) Require(names ...string) *ComponentInfo {
+	info.Requires = append(info.Requires, names...)
+	return info
+}
+
+// Range limits the values SetField accepts for a number field.
+func (info *ComponentInfo) Range(field string, min, max float64) *ComponentInfo {
+	f := info.Field(field)
+	if f == nil {
+		panic(fmt.Sprintf("component %s has no field %s", info.Name, field))
+	}
+	f.HasRange, f.Min, f.Max = true, min, max
+	return info
+}
+
+func (info *ComponentInfo) Field(name string) *FieldInfo {
+	for _, f := range info.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (info *ComponentInfo) New() Component {
+	return info.Factory()
+}
+
+func ComponentInfoByName(name string) *ComponentInfo {
+	return componentInfos[name]
+}
+
+// ComponentInfoOf returns the registration of the component type, nil if it is not registered.
+func ComponentInfoOf(c Component) *ComponentInfo {
+	return componentTypes[reflect.TypeOf(c)]
+}
+
+func NewComponentByName(name string) (Component, error) {
+	info, exists := componentInfos[name]
+	if !exists {
+		return nil, fmt.Errorf("component %s is not registered", name)
+	}
+	return info.Factory(), nil
+}
+
+// ComponentName returns the registered name of the component type.
+func ComponentName(c Component) (string, bool) {
+	info, exists := componentTypes[reflect.TypeOf(c)]
+	if !exists {
+		return "", false
+	}
+	return info.Name, true
+}
+
+func RegisteredComponents() []string {
+	names := make([]string, 0, len(componentInfos))
+	for name := range componentInfos {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetField returns the value of an exported field of c, nested fields are separated by dots (Color.A).
+func GetField(c Component, field string) (interface{}, error) {
+	v, err := fieldValue(c, field)
+	if err != nil {
+		return nil, err
+	}
+	return v.Interface(), nil
+}
+
+// SetField sets an exported field of c, numbers are converted and checked against the field range,
+// other values that don't match the field type go through JSON (maps for structs, arrays for slices).
+func SetField(c Component, field string, value interface{}) error {
+	v, err := fieldValue(c, field)
+	if err != nil {
+		return err
+	}
+	if info := ComponentInfoOf(c); info != nil {
+		if f := info.Field(field); f != nil && f.HasRange {
+			n, ok := toFloat(value)
+			if !ok {
+				return fmt.Errorf("%s.%s: %v is not a number", info.Name, field, value)
+			}
+			if n < f.Min || n > f.Max {
+				return fmt.Errorf("%s.%s: %v is out of range [%v, %v]", info.Name, field, value, f.Min, f.Max)
+			}
+		}
+	}
+	if err := setValue(v, value); err != nil {
+		return fmt.Errorf("%v.%s: %v", reflect.TypeOf(c).Elem(), field, err)
+	}
+	return nil
+}
+
+func fieldValue(c Component, field string) (reflect.Value, error) {
+	v := reflect.ValueOf(c).Elem()
+	for _, name := range strings.Split(field, ".") {
+		if v.Kind() != reflect.Struct {
+			return v, fmt.Errorf("%v.%s is not a struct", reflect.TypeOf(c).Elem(), field)
+		}
+		v = v.FieldByName(name)
+		if !v.IsValid() || !v.CanSet() {
+			return v, fmt.Errorf("%v has no exported field %s", reflect.TypeOf(c).Elem(), field)
+		}
+	}
+	return v, nil
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+		return 0, false
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		return float64(v.Int()), true
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uintptr:
+		return float64(v.Uint()), true
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// addRequiredComponents adds the components com requires that g doesn't have yet,
+// pending are the components that are being added and will be there too.
+func addRequiredComponents(g *GameObject, com Component, pending []Component) {
+	info := ComponentInfoOf(com)
+	if info == nil {
+		return
+	}
+	pending = append(pending, com)
+	for _, name := range info.Requires {
+		req := componentInfos[name]
+		if req == nil {
+			panic(fmt.Sprintf("component %s requires %s which is not registered", info.Name, name))
+		}
+		if g.ComponentTypeOf(req.Type) != Nil || hasComponentOfType(pending, req.Type) {
+			continue
+		}
+		c := req.Factory()
+		addRequiredComponents(g, c, pending)
+		g.addComponent(c)
+		g.required = append(g.required, c)
+	}
+}
+
+func hasComponentOfType(components []Component, typ reflect.Type) bool {
+	for _, c := range components {
+		if reflect.TypeOf(c) == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// addMissingComponents adds the components that the components of g require after all of them were added,
+// so the order of the components doesn't matter.
+func addMissingComponents(g *GameObject) {
+	for _, c := range g.Components() {
+		addRequiredComponents(g, c, nil)
+	}
+}
@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"testing"
+)
+
+type rangedComponent struct {
+	BaseComponent
+	Speed float32 `range:"0,10"`
+	Name  string
+	Body  *Physics
+}
+
+func init() {
+	RegisterComponent("rangedComponent", func() Component { return &rangedComponent{BaseComponent: NewComponent()} }).Require("Physics")
+}
+
+func TestRegistryFields(t *testing.T) {
+	info := ComponentInfoByName("rangedComponent")
+	if info == nil {
+		t.Fatal("component is not registered")
+	}
+	if len(info.Fields) != 3 {
+		t.Fatalf("found %d fields, expected 3", len(info.Fields))
+	}
+	if f := info.Field("Speed"); !f.HasRange || f.Min != 0 || f.Max != 10 {
+		t.Errorf("Speed range is %v %v-%v, expected 0-10", f.HasRange, f.Min, f.Max)
+	}
+	if info.Field("Body").Serialized {
+		t.Error("pointer fields should not be serialized")
+	}
+
+	c := info.New()
+	if err := SetField(c, "Speed", 5); err != nil {
+		t.Error(err)
+	}
+	if err := SetField(c, "Speed", 11); err == nil {
+		t.Error("expected an out of range error")
+	}
+	if v, _ := GetField(c, "Speed"); v != float32(5) {
+		t.Errorf("Speed is %v, expected 5", v)
+	}
+}
+
+func TestRequiredComponents(t *testing.T) {
+	g := NewGameObject("Required")
+	g.AddComponent(ComponentInfoByName("rangedComponent").New())
+	if g.Physics == nil {
+		t.Fatal("required Physics was not added")
+	}
+	if len(g.components) != 2 || g.components[0] != g.Physics {
+		t.Error("required components should be added before the component that needs them")
+	}
+	if c := g.Clone(); len(c.components) != 2 {
+		t.Errorf("the clone has %d components, expected 2", len(c.components))
+	}
+
+	//A component that is added after the one that requires it replaces the one that was added for it.
+	g = NewGameObject("RequiredLater")
+	g.AddComponent(ComponentInfoByName("rangedComponent").New())
+	p := NewPhysics(false, 2, 2)
+	g.AddComponent(p)
+	if len(g.components) != 2 || g.components[0] != p || g.Physics != p {
+		t.Error("the explicit Physics should replace the required one")
+	}
+}
@@ -58,7 +58,11 @@ func initDefaultPlane() {
 }
 
 func InsideScreen(ratio float32, position Vector, scale Vector) bool {
-	cameraPos := GetScene().SceneBase().Camera.Transform().WorldPosition()
+	camera := currentCamera()
+	if camera == nil {
+		return false
+	}
+	cameraPos := camera.Transform().WorldPosition()
 
 	bigScale := scale.X * ratio
 	if scale.Y > bigScale {
@@ -78,6 +82,10 @@ func InsideScreen(ratio float32, position Vector, scale Vector) bool {
 }
 
 func DrawSprite(tex *Texture, uv UV, position Vector, scale Vector, rotation float32, aling AlignType, color Color) {
+	camera := currentCamera()
+	if camera == nil {
+		return
+	}
 	if !InsideScreen(uv.Ratio, position, scale) {
 		return
 	}
@@ -97,7 +105,6 @@ func DrawSprite(tex *Texture, uv UV, position Vector, scale Vector, rotation flo
 	v := Align(aling)
 	v.X *= uv.Ratio
 
-	camera := GetScene().SceneBase().Camera
 	view := camera.InvertedMatrix()
 	model := Identity()
 	model.Translate(v.X, v.Y, 0)
@@ -118,11 +125,16 @@ func DrawSprite(tex *Texture, uv UV, position Vector, scale Vector, rotation flo
 	tex.Bind()
 
 	gl.DrawArrays(gl.QUADS, 0, 4)
+	CountDrawCall()
 
 	internalMaterial.End(nil)
 }
 
 func DrawSprites(tex *Texture, uvs []UV, positions []Vector, scales []Vector, rotations []float32, alings []AlignType, colors []Color) {
+	camera := currentCamera()
+	if camera == nil {
+		return
+	}
 
 	internalMaterial.Begin(nil)
 
@@ -136,7 +148,6 @@ func DrawSprites(tex *Texture, uvs []UV, positions []Vector, scales []Vector, ro
 
 	defaultVAO.Bind()
 
-	camera := GetScene().SceneBase().Camera
 	view := camera.InvertedMatrix()
 	mv.UniformMatrix4fv(false, view)
 	mp.UniformMatrix4f(false, (*[16]float32)(camera.Projection))
@@ -170,6 +181,7 @@ func DrawSprites(tex *Texture, uvs []UV, positions []Vector, scales []Vector, ro
 		of.Uniform2f(uv.U1, uv.V1)
 
 		gl.DrawArrays(gl.QUADS, 0, 4)
+		CountDrawCall()
 	}
 
 	internalMaterial.End(nil)
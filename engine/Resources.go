@@ -2,6 +2,10 @@ package engine
 
 import (
 	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
 	//"github.com/vova616/gl"
 )
 
@@ -11,8 +15,16 @@ type Resource interface {
 	Release()
 }
 
+// MemorySizer is a resource that knows how many bytes it uses, the asset report uses it.
+type MemorySizer interface {
+	MemorySize() int64
+}
+
 var (
-	ResourceManager = &Resources{make(map[ResID]Resource)}
+	Assets = NewAssetManager()
+
+	// Deprecated: ResourceManager is the asset manager, use Assets.
+	ResourceManager = Assets
 )
 
 type MemHandle struct {
@@ -31,43 +43,495 @@ func (m *MemHandle) Bytes() []byte {
 	return m.Buff
 }
 
-type Resources struct {
-	Resources map[ResID]Resource
+func (m *MemHandle) MemorySize() int64 {
+	return int64(len(m.Buff))
 }
 
-func (r *Resources) Add(res Resource) error {
-	_, exists := r.Resources[res]
-	if exists {
-		return fmt.Errorf("Cannot add res %d %v", res, res)
+// Sound keeps the bytes of a sound file, the engine has no audio output so it's up to the game to play it.
+type Sound struct {
+	Path string
+	Data []byte
+}
+
+func LoadSound(path string) (*Sound, error) {
+	data, err := Files.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return &Sound{path, data}, nil
+}
+
+func (s *Sound) Release() {
+	s.Data = nil
+}
+
+func (s *Sound) MemorySize() int64 {
+	return int64(len(s.Data))
+}
+
+// AssetLoader loads the asset at path, see AssetManager.RegisterLoader.
+type AssetLoader func(path string) (Resource, error)
+
+type assetEntry struct {
+	//key is the id of a resource that was added with Add/AddManual, kind and path are set for loaded assets.
+	key  ResID
+	kind string
+	path string
+	res  Resource
+	refs int
+}
+
+// AssetManager loads assets by path and caches them, loading the same path again returns the same asset:
+//
+//	tex, err := engine.Assets.Texture("./data/rect.png")
+//	font, err := engine.Assets.Font("./data/Fonts/arial.ttf", 24)
+//
+// Every scene that loads an asset (in Load, Prepare or its routines) holds a reference to it,
+// the references are dropped when the scene is unloaded and assets no scene holds are released after the next scene is loaded,
+// so assets that are shared between scenes survive a scene switch.
+// A scene that loads in the background loads through loader.Assets() in Prepare and its uploads run for it,
+// assets loaded outside of any scene are kept until they are unloaded.
+//
+// Resources that are created without a path (NewTexture2, uploaded atlases) are added with Add and belong to the scene the same way.
+type AssetManager struct {
+	*assetCache
+	//scene holds the references of the assets this manager loads, it is nil for Assets
+	//which gives them to the scene that loads or runs on the main thread.
+	scene *SceneData
+}
+
+// assetCache is shared by Assets and the managers of the scene loaders.
+type assetCache struct {
+	//Assets can be loaded by scene loaders in the background.
+	mutex   sync.Mutex
+	paths   map[string]*assetEntry
+	manual  map[ResID]*assetEntry
+	byRes   map[Resource]*assetEntry
+	loaders map[string]AssetLoader
+	//loading is the scene whose Load or loader upload runs on the main thread right now.
+	loading *SceneData
+	//running is the scene GetScene returns on the main thread, the engine sets it when the scene changes
+	//so loader goroutines don't read the scene globals.
+	running *SceneData
+}
+
+func NewAssetManager() *AssetManager {
+	return &AssetManager{assetCache: &assetCache{
+		paths:   make(map[string]*assetEntry),
+		manual:  make(map[ResID]*assetEntry),
+		byRes:   make(map[Resource]*assetEntry),
+		loaders: make(map[string]AssetLoader),
+	}}
+}
+
+// forScene returns a manager that shares the assets of m and gives the ones it loads to sd.
+func (m *AssetManager) forScene(sd *SceneData) *AssetManager {
+	return &AssetManager{m.assetCache, sd}
+}
+
+// builtinLoader returns the loaders of the assets the engine knows, fonts and shaders take more than a path.
+func builtinLoader(kind string) (AssetLoader, bool) {
+	switch kind {
+	case "texture":
+		return func(path string) (Resource, error) {
+			tex, err := LoadTexture(path)
+			if err != nil {
+				return nil, err
+			}
+			return tex, nil
+		}, true
+	case "atlas":
+		return func(path string) (Resource, error) {
+			atlas, err := loadAtlas(path)
+			if err != nil {
+				return nil, err
+			}
+			return atlas, nil
+		}, true
+	case "sound":
+		return func(path string) (Resource, error) {
+			sound, err := LoadSound(path)
+			if err != nil {
+				return nil, err
+			}
+			return sound, nil
+		}, true
+	}
+	return nil, false
+}
+
+// RegisterLoader sets the loader of kind, Load(kind, path) uses it. The texture, atlas and sound loaders can be replaced.
+func (m *AssetManager) RegisterLoader(kind string, loader AssetLoader) {
+	m.mutex.Lock()
+	m.loaders[kind] = loader
+	m.mutex.Unlock()
+}
+
+// Load returns the cached asset of kind at path or loads it with the loader of kind.
+func (m *AssetManager) Load(kind, path string) (Resource, error) {
+	m.mutex.Lock()
+	loader, exists := m.loaders[kind]
+	m.mutex.Unlock()
+	if !exists {
+		loader, exists = builtinLoader(kind)
+	}
+	if !exists {
+		return nil, fmt.Errorf("no loader for %q assets", kind)
+	}
+	return m.load(kind, path, path, func() (Resource, error) { return loader(path) })
+}
+
+// Texture loads an image file as a texture, it has to be called on the main thread (or in SceneLoader.Upload).
+func (m *AssetManager) Texture(path string) (*Texture, error) {
+	res, err := m.Load("texture", path)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*Texture), nil
+}
+
+// Atlas loads a directory with AtlasLoadDirectory, or a single image, and builds the atlas.
+func (m *AssetManager) Atlas(path string) (*ManagedAtlas, error) {
+	res, err := m.Load("atlas", path)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*ManagedAtlas), nil
+}
+
+func (m *AssetManager) Font(path string, size float64) (*Font, error) {
+	key := path + "@" + strconv.FormatFloat(size, 'g', -1, 64)
+	res, err := m.load("font", path, key, func() (Resource, error) {
+		font, err := NewFont(path, size)
+		if err != nil {
+			return nil, err
+		}
+		return font, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*Font), nil
+}
+
+// Shader loads a material from shader files with LoadBasicMaterial.
+func (m *AssetManager) Shader(vertexPath, fragmentPath string) (*BasicMaterial, error) {
+	key := vertexPath + "|" + fragmentPath
+	res, err := m.load("shader", vertexPath, key, func() (Resource, error) {
+		material, err := LoadBasicMaterial(vertexPath, fragmentPath)
+		if err != nil {
+			material.Release()
+			return nil, err
+		}
+		return material, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.(*BasicMaterial), nil
+}
+
+func (m *AssetManager) Sound(path string) (*Sound, error) {
+	res, err := m.Load("sound", path)
+	if err != nil {
+		return nil, err
+	}
+	return res.(*Sound), nil
+}
+
+func (m *AssetManager) load(kind, path, key string, load func() (Resource, error)) (Resource, error) {
+	key = kind + ":" + key
+	m.mutex.Lock()
+	if e, exists := m.paths[key]; exists {
+		m.reference(e)
+		m.mutex.Unlock()
+		return e.res, nil
+	}
+	m.mutex.Unlock()
+
+	//The lock is not held while loading, loaders add their textures with Add.
+	res, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.Lock()
+	m.adopt(res)
+	if e, exists := m.paths[key]; exists {
+		//Another goroutine loaded it first.
+		m.reference(e)
+		m.mutex.Unlock()
+		releaseSafe(res)
+		return e.res, nil
+	}
+	e := &assetEntry{kind: kind, path: path, res: res}
+	m.paths[key] = e
+	m.byRes[res] = e
+	m.reference(e)
+	m.mutex.Unlock()
+	LogAssets.Debug("Asset loaded", "kind", kind, "path", path)
+	return res, nil
+}
+
+func loadAtlas(path string) (*ManagedAtlas, error) {
+	info, err := Files.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	var atlas *ManagedAtlas
+	if info.IsDir() {
+		atlas, err = AtlasLoadDirectory(path)
 	} else {
-		r.Resources[res] = res
+		atlas = NewManagedAtlas(1024, 1024)
+		err, _ = atlas.LoadImage(path)
 	}
-	return nil
+	if err == nil {
+		err = atlas.BuildAtlas()
+	}
+	if err != nil {
+		Assets.Unload(atlas)
+		return nil, err
+	}
+	return atlas, nil
+}
+
+// adopt removes the entries that were added with Add for res and the resources it is made of, the loaded asset owns them.
+func (m *AssetManager) adopt(res Resource) {
+	parts := []Resource{res}
+	switch r := res.(type) {
+	case *Font:
+		parts = append(parts, r.Texture)
+	case *ManagedAtlas:
+		parts = append(parts, r.Texture)
+	}
+	for _, part := range parts {
+		if part == nil {
+			continue
+		}
+		if e, exists := m.byRes[part]; exists && e.kind == "" {
+			delete(m.manual, e.key)
+			delete(m.byRes, part)
+		}
+	}
+}
+
+// owner returns the scene that holds the references of assets that are loaded now, nil if there is none.
+// It's called with the lock held.
+func (m *AssetManager) owner() *SceneData {
+	if m.scene != nil {
+		return m.scene
+	}
+	if m.loading != nil {
+		return m.loading
+	}
+	return m.running
+}
+
+func (m *AssetManager) reference(e *assetEntry) {
+	sd := m.owner()
+	if sd == nil {
+		e.refs++
+		return
+	}
+	if sd.assetsReleased {
+		//The scene is gone, the asset is released by the next collect if nothing else holds it.
+		return
+	}
+	if sd.assets == nil {
+		sd.assets = make(map[*assetEntry]bool)
+	}
+	if !sd.assets[e] {
+		sd.assets[e] = true
+		e.refs++
+	}
+}
+
+// setLoading sets the scene that owns what the main thread loads and returns the one before it.
+func (m *AssetManager) setLoading(sd *SceneData) *SceneData {
+	m.mutex.Lock()
+	last := m.loading
+	m.loading = sd
+	m.mutex.Unlock()
+	return last
+}
+
+func (m *AssetManager) setRunning(sd *SceneData) {
+	m.mutex.Lock()
+	m.running = sd
+	m.mutex.Unlock()
+}
+
+// releaseScene drops the references of the scene, collect releases the assets nothing holds.
+func (m *AssetManager) releaseScene(sd *SceneData) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for e := range sd.assets {
+		e.refs--
+	}
+	sd.assets = nil
+	sd.assetsReleased = true
+	if m.loading == sd {
+		m.loading = nil
+	}
+}
+
+// collect releases the assets that no scene holds.
+func (m *AssetManager) collect() {
+	m.mutex.Lock()
+	released := make([]*assetEntry, 0)
+	for key, e := range m.paths {
+		if e.refs <= 0 {
+			delete(m.paths, key)
+			delete(m.byRes, e.res)
+			released = append(released, e)
+		}
+	}
+	for key, e := range m.manual {
+		if e.refs <= 0 {
+			delete(m.manual, key)
+			delete(m.byRes, e.res)
+			released = append(released, e)
+		}
+	}
+	m.mutex.Unlock()
+
+	for _, e := range released {
+		if e.kind != "" {
+			LogAssets.Debug("Asset released", "kind", e.kind, "path", e.path)
+		}
+		releaseSafe(e.res)
+	}
+}
+
+func (m *AssetManager) Add(res Resource) error {
+	return m.AddManual(res, res)
 }
 
-func (r *Resources) AddManual(res Resource, key interface{}) error {
-	_, exists := r.Resources[key]
+// AddManual adds a resource that was not loaded by path under key, it belongs to the scene that is loading or running.
+func (m *AssetManager) AddManual(res Resource, key interface{}) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	_, exists := m.manual[key]
 	if exists {
 		return fmt.Errorf("Cannot add res %d %v", res, res)
-	} else {
-		r.Resources[res] = res
 	}
+	e := &assetEntry{key: key, res: res}
+	m.manual[key] = e
+	m.byRes[res] = e
+	m.reference(e)
 	return nil
 }
 
-func (r *Resources) Release() {
-	for _, res := range r.Resources {
-		releaseSafe(res)
+// Retain adds a reference to res that no scene holds, it is kept until Unload is called.
+func (m *AssetManager) Retain(res Resource) {
+	m.mutex.Lock()
+	if e, exists := m.byRes[res]; exists {
+		e.refs++
 	}
-	r.Resources = make(map[ResID]Resource)
+	m.mutex.Unlock()
 }
 
-func (r *Resources) ReleaseResource(res interface{}) {
-	resData, exists := r.Resources[res]
+// Unload releases res now even if scenes still hold it.
+func (m *AssetManager) Unload(res Resource) {
+	m.mutex.Lock()
+	e, exists := m.byRes[res]
+	if exists {
+		delete(m.byRes, res)
+		if e.kind != "" {
+			for key, p := range m.paths {
+				if p == e {
+					delete(m.paths, key)
+				}
+			}
+		} else {
+			delete(m.manual, e.key)
+		}
+	}
+	m.mutex.Unlock()
 	if exists {
-		resData.Release()
-		delete(r.Resources, res)
+		releaseSafe(res)
+	}
+}
+
+// Deprecated: use Unload.
+func (m *AssetManager) ReleaseResource(res Resource) {
+	m.Unload(res)
+}
+
+// Release releases every asset.
+func (m *AssetManager) Release() {
+	m.mutex.Lock()
+	all := make([]Resource, 0, len(m.byRes))
+	for res := range m.byRes {
+		all = append(all, res)
+	}
+	m.paths = make(map[string]*assetEntry)
+	m.manual = make(map[ResID]*assetEntry)
+	m.byRes = make(map[Resource]*assetEntry)
+	m.mutex.Unlock()
+
+	for _, res := range all {
+		releaseSafe(res)
+	}
+}
+
+type AssetInfo struct {
+	//Kind is empty for resources that were added with Add.
+	Kind string
+	Path string
+	Type string
+	Refs int
+	//Size is the number of bytes the asset uses, 0 if it's unknown.
+	Size int64
+}
+
+// Report returns every asset from the biggest to the smallest.
+func (m *AssetManager) Report() []AssetInfo {
+	m.mutex.Lock()
+	infos := make([]AssetInfo, 0, len(m.byRes))
+	for res, e := range m.byRes {
+		info := AssetInfo{Kind: e.kind, Path: e.path, Type: fmt.Sprintf("%T", res), Refs: e.refs}
+		if s, ok := res.(MemorySizer); ok {
+			info.Size = s.MemorySize()
+		}
+		infos = append(infos, info)
+	}
+	m.mutex.Unlock()
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		if infos[i].Size != infos[j].Size {
+			return infos[i].Size > infos[j].Size
+		}
+		return infos[i].Path < infos[j].Path
+	})
+	return infos
+}
+
+// MemoryUsage returns the bytes all of the assets use.
+func (m *AssetManager) MemoryUsage() int64 {
+	var total int64
+	for _, info := range m.Report() {
+		total += info.Size
+	}
+	return total
+}
+
+// WriteReport writes the report as a table.
+func (m *AssetManager) WriteReport(w io.Writer) error {
+	infos := m.Report()
+	var total int64
+	for _, info := range infos {
+		total += info.Size
+		path := info.Path
+		if path == "" {
+			path = "-"
+		}
+		if _, err := fmt.Fprintf(w, "%10d %4d %-20s %s\n", info.Size, info.Refs, info.Type, path); err != nil {
+			return err
+		}
 	}
+	_, err := fmt.Fprintf(w, "%10d bytes in %d assets\n", total, len(infos))
+	return err
 }
 
 func releaseSafe(res Resource) {
@@ -77,6 +541,6 @@ func releaseSafe(res Resource) {
 
 func recoverFromPanic(res Resource) {
 	if err := recover(); err != nil {
-		fmt.Printf("release failed: ID:%d Res:%v Error:%v\n", res, res, err)
+		LogAssets.Error("Resource release failed", "resource", res, "err", err)
 	}
 }
@@ -0,0 +1,86 @@
+package engine
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestAssetsSurviveSceneSwitch(t *testing.T) {
+	m := NewAssetManager()
+	loads := 0
+	m.RegisterLoader("test", func(path string) (Resource, error) {
+		loads++
+		return Allocate(100), nil
+	})
+
+	first, second := NewScene("First"), NewScene("Second")
+	m.setLoading(first)
+	shared, _ := m.Load("test", "shared")
+	only, _ := m.Load("test", "first")
+	again, _ := m.Load("test", "shared")
+	if again != shared || loads != 2 {
+		t.Fatalf("the second load was not cached, %d loads", loads)
+	}
+
+	//The old scene is destroyed before the new one loads, like LoadScene does.
+	m.releaseScene(first)
+	m.setLoading(second)
+	if res, _ := m.Load("test", "shared"); res != shared {
+		t.Fatal("a released but not collected asset was loaded again")
+	}
+	m.setLoading(nil)
+	m.collect()
+
+	if shared.(*MemHandle).Buff == nil {
+		t.Error("the asset the new scene uses was released")
+	}
+	if only.(*MemHandle).Buff != nil {
+		t.Error("the asset only the old scene used was not released")
+	}
+	if m.MemoryUsage() != 100 {
+		t.Errorf("memory usage is %d, expected 100", m.MemoryUsage())
+	}
+	var buf bytes.Buffer
+	m.WriteReport(&buf)
+	if !bytes.Contains(buf.Bytes(), []byte("shared")) {
+		t.Errorf("the report doesn't have the asset:\n%s", buf.String())
+	}
+
+	m.Unload(shared)
+	if shared.(*MemHandle).Buff != nil || len(m.Report()) != 0 {
+		t.Error("Unload didn't release the asset")
+	}
+}
+
+func TestAddManualUsesKey(t *testing.T) {
+	m := NewAssetManager()
+	if err := m.AddManual(Allocate(1), "key"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.AddManual(Allocate(1), "key"); err == nil {
+		t.Error("a second resource was added under the same key")
+	}
+}
+
+func TestAssetsLoadedInBackground(t *testing.T) {
+	m := NewAssetManager()
+	m.RegisterLoader("test", func(path string) (Resource, error) {
+		return Allocate(10), nil
+	})
+	running := NewScene("Running")
+	m.setRunning(running)
+
+	//A loader goroutine gets the scene the main thread runs without reading the scene globals.
+	done := make(chan Resource)
+	go func() {
+		res, _ := m.Load("test", "background")
+		done <- res
+	}()
+	res := <-done
+
+	m.releaseScene(running)
+	m.collect()
+	if res.(*MemHandle).Buff != nil {
+		t.Error("the asset was not held by the running scene")
+	}
+}
@@ -1,11 +1,23 @@
 package engine
 
-//import "log"
+import (
+	"github.com/vova616/garageEngine/engine/input"
+)
 
 type SceneData struct {
 	name        string
 	gameObjects []*GameObject
 	Camera      *Camera
+
+	noUpdate, noDraw, noInput bool
+	//Scenes that PushScene took the input from.
+	blockedScenes []*SceneData
+
+	events *EventBus
+
+	//assets the scene holds a reference to, see AssetManager.
+	assets         map[*assetEntry]bool
+	assetsReleased bool
 }
 
 type Scene interface {
@@ -31,14 +43,203 @@ func (s *SceneData) SceneBase() *SceneData {
 }
 
 func (s *SceneData) AddGameObject(gameObject ...*GameObject) {
+	for _, g := range gameObject {
+		g.scene = s
+		reindex(g)
+	}
 	s.gameObjects = append(s.gameObjects, gameObject...)
 }
 
 func (s *SceneData) RemoveGameObject(g *GameObject) {
 	for i, c := range s.gameObjects {
 		if g == c {
+			g.scene = nil
 			s.gameObjects = append(s.gameObjects[:i], s.gameObjects[i+1:]...)
+			reindex(g)
+			break
+		}
+	}
+}
+
+// SetUpdating controls if the scene runs Start/FixedUpdate/Update/LateUpdate routines.
+// Physics bodies are shared between scenes and keep simulating.
+func (s *SceneData) SetUpdating(update bool) {
+	s.noUpdate = !update
+}
+
+func (s *SceneData) Updating() bool {
+	return !s.noUpdate
+}
+
+func (s *SceneData) SetDrawing(draw bool) {
+	s.noDraw = !draw
+}
+
+func (s *SceneData) Drawing() bool {
+	return !s.noDraw
+}
+
+// SetInput controls if the scene sees the keyboard and mouse, when false the input package
+// reports nothing pressed while the scene's routines run.
+func (s *SceneData) SetInput(input bool) {
+	s.noInput = !input
+}
+
+func (s *SceneData) Input() bool {
+	return !s.noInput
+}
+
+/*
+	Additive scenes:
+	activeScenes[0] is always the main scene (LoadScene replaces all of them),
+	scenes loaded with LoadSceneAdditive/PushScene are updated and drawn after it, so they are drawn on top.
+*/
+
+// LoadSceneAdditive loads scene on top of the active scenes and returns the new instance.
+func LoadSceneAdditive(scene Scene) Scene {
+	sn := scene.New()
+
+	last := currentScene
+	setCurrentScene(sn)
+	sn.Load()
+	setCurrentScene(last)
+
+	activeScenes = append(activeScenes, sn)
+	return sn
+}
+
+// PushScene loads scene additively and takes the input from every scene under it until it's popped.
+func PushScene(scene Scene) Scene {
+	sn := LoadSceneAdditive(scene)
+	sd := sn.SceneBase()
+	for _, s := range activeScenes {
+		below := s.SceneBase()
+		if below != sd && below.Input() {
+			below.SetInput(false)
+			sd.blockedScenes = append(sd.blockedScenes, below)
+		}
+	}
+	return sn
+}
+
+// PopScene unloads the top scene, the main scene is never popped.
+func PopScene() Scene {
+	if len(activeScenes) < 2 {
+		return nil
+	}
+	s := activeScenes[len(activeScenes)-1]
+	UnloadScene(s)
+	return s
+}
+
+// UnloadScene destroys all of the scene game objects and removes it from the active scenes.
+// Inside the game loop it's done at the start of the next frame.
+func UnloadScene(scene Scene) {
+	if scene == mainScene {
+		return
+	}
+	if insideGameloop {
+		unloadScenes = append(unloadScenes, scene)
+		return
+	}
+
+	for i, s := range activeScenes {
+		if s == scene {
+			activeScenes = append(activeScenes[:i], activeScenes[i+1:]...)
+			destroyScene(s)
+			for _, b := range s.SceneBase().blockedScenes {
+				b.SetInput(true)
+			}
+			s.SceneBase().blockedScenes = nil
+			Assets.collect()
 			break
 		}
 	}
 }
+
+// Scenes returns the active scenes, the main scene first.
+func Scenes() []Scene {
+	arr := make([]Scene, len(activeScenes))
+	copy(arr, activeScenes)
+	return arr
+}
+
+func MainScene() Scene {
+	return mainScene
+}
+
+func destroyScene(scene Scene) {
+	last := currentScene
+	setCurrentScene(scene)
+
+	sd := scene.SceneBase()
+	for _, g := range sd.gameObjects {
+		if g != nil {
+			g.Destroy()
+		}
+	}
+	IterAll(sd.gameObjects, destoyGameObject)
+	sd.gameObjects = nil
+	sd.events = nil
+	Assets.releaseScene(sd)
+
+	setCurrentScene(last)
+}
+
+func unloadPendingScenes() {
+	for len(unloadScenes) > 0 {
+		s := unloadScenes[0]
+		unloadScenes = unloadScenes[1:]
+		UnloadScene(s)
+	}
+}
+
+// iterScenes runs f on the game objects of every active scene, the scene is set as the current scene
+// so GetScene returns it while f runs.
+func iterScenes(f func(*GameObject), filter func(*SceneData) bool) {
+	for _, s := range activeScenes {
+		sd := s.SceneBase()
+		if filter != nil && !filter(sd) {
+			continue
+		}
+		setCurrentScene(s)
+		Iter(sd.gameObjects, f)
+	}
+	setCurrentScene(nil)
+	input.Block(false)
+}
+
+// runScenesPhase is iterScenes for component callbacks, see runPhase.
+func runScenesPhase(objFilter func(*GameObject) bool, call func(Component), filter func(*SceneData) bool) {
+	for _, s := range activeScenes {
+		sd := s.SceneBase()
+		if filter != nil && !filter(sd) {
+			continue
+		}
+		setCurrentScene(s)
+		runPhase(sd.gameObjects, objFilter, call)
+	}
+	setCurrentScene(nil)
+	input.Block(false)
+}
+
+// iterAllScenes runs f on every game object of the active scenes, including inactive ones.
+func iterAllScenes(f func(*GameObject)) {
+	for _, s := range activeScenes {
+		setCurrentScene(s)
+		IterAll(s.SceneBase().gameObjects, f)
+	}
+	setCurrentScene(nil)
+}
+
+func updatingScene(sd *SceneData) bool {
+	if sd.Updating() {
+		input.Block(!sd.Input())
+		return true
+	}
+	return false
+}
+
+func drawingScene(sd *SceneData) bool {
+	return sd.Drawing()
+}
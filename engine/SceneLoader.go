@@ -0,0 +1,236 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// AsyncScene is a scene that can prepare its assets (decode images, pack atlases, render fonts)
+// away from the main thread. Prepare must not call OpenGL directly, GL work goes through loader.Upload.
+// Load is called on the main thread after Prepare returns and should only build the game objects.
+type AsyncScene interface {
+	Scene
+	Prepare(loader *SceneLoader) error
+}
+
+// ErrLoadCancelled is the error of a scene loader that was cancelled by another load.
+var ErrLoadCancelled = errors.New("scene load cancelled")
+
+// loadCancelled unwinds Prepare when it uploads after its load was cancelled.
+type loadCancelled struct{}
+
+// SceneLoader reports the progress of a scene load and runs GL uploads on the main thread.
+type SceneLoader struct {
+	//OnError is called on the main thread when Prepare fails, set it right after LoadSceneAsync.
+	//Without it the scene that was running before the loading screen is loaded again.
+	OnError func(loader *SceneLoader, err error)
+
+	scene   Scene
+	loading Scene
+	async   bool
+	//previous is the main scene the loading screen replaced.
+	previous Scene
+	loaded   *Future
+
+	mutex     sync.Mutex
+	progress  float32
+	done      bool
+	cancelled bool
+	err       error
+}
+
+var (
+	sceneLoader *SceneLoader
+	nextLoader  *SceneLoader
+)
+
+// LoadSceneAsync prepares scene in the background while loadingScreen (can be nil) is shown, and switches to it when it's ready.
+func LoadSceneAsync(scene Scene, loadingScreen Scene) *SceneLoader {
+	loader := &SceneLoader{scene: scene.New(), loading: loadingScreen, async: true, loaded: NewFuture()}
+	if insideGameloop {
+		nextLoader = loader
+		return loader
+	}
+	loader.start()
+	return loader
+}
+
+// CurrentSceneLoader returns the loader of the scene that is loading in the background, nil if there is none.
+func CurrentSceneLoader() *SceneLoader {
+	return sceneLoader
+}
+
+func (l *SceneLoader) start() {
+	if sceneLoader != nil {
+		sceneLoader.cancel()
+		sceneLoader = nil
+	}
+	if l.loading != nil {
+		l.previous = mainScene
+		LoadScene(l.loading)
+	}
+	sceneLoader = l
+
+	go l.prepare()
+}
+
+func (l *SceneLoader) prepare() {
+	defer func() {
+		if p := recover(); p != nil {
+			if _, cancelled := p.(loadCancelled); cancelled {
+				l.finish(ErrLoadCancelled)
+				return
+			}
+			logPanic(p)
+			l.finish(fmt.Errorf("%v", p))
+		}
+	}()
+	if as, ok := l.scene.(AsyncScene); ok {
+		l.finish(as.Prepare(l))
+	} else {
+		l.finish(nil)
+	}
+}
+
+func (l *SceneLoader) finish(err error) {
+	l.mutex.Lock()
+	l.done = true
+	l.err = err
+	if err == nil {
+		l.progress = 1
+	}
+	l.mutex.Unlock()
+}
+
+func (l *SceneLoader) cancel() {
+	l.mutex.Lock()
+	cancelled := l.cancelled
+	l.cancelled = true
+	l.mutex.Unlock()
+	if !cancelled {
+		Assets.releaseScene(l.scene.SceneBase())
+		l.loaded.Fail(ErrLoadCancelled)
+	}
+}
+
+func (l *SceneLoader) isCancelled() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.cancelled
+}
+
+// SetProgress reports how much of the preparation is done, from 0 to 1.
+func (l *SceneLoader) SetProgress(progress float32) {
+	if l == nil {
+		return
+	}
+	l.mutex.Lock()
+	l.progress = progress
+	l.mutex.Unlock()
+}
+
+func (l *SceneLoader) Progress() float32 {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.progress
+}
+
+func (l *SceneLoader) Done() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.done
+}
+
+func (l *SceneLoader) Err() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.err
+}
+
+func (l *SceneLoader) Scene() Scene {
+	return l.scene
+}
+
+// Loaded returns a future that is done when the scene runs, its value is the scene.
+// It fails with the error of Prepare or with ErrLoadCancelled:
+//
+//	engine.LoadSceneAsync(GameSceneGeneral, engine.LoadingSceneGeneral).Loaded().Then(func(scene interface{}, err error) {
+//		...
+//	})
+func (l *SceneLoader) Loaded() *Future {
+	return l.loaded
+}
+
+// Assets returns the asset manager Prepare loads with, the assets it loads belong to the scene that is loading.
+func (l *SceneLoader) Assets() *AssetManager {
+	if l == nil {
+		return Assets
+	}
+	return Assets.forScene(l.scene.SceneBase())
+}
+
+// Upload runs fn on the main thread and waits for it, use it for every OpenGL call inside Prepare.
+// Assets that fn loads or adds belong to the loading scene. Outside of an async load (or on a nil loader) fn just runs.
+// Prepare stops at Upload once the load was cancelled, Err returns ErrLoadCancelled.
+func (l *SceneLoader) Upload(fn func()) {
+	if l == nil || !l.async {
+		fn()
+		return
+	}
+	if l.isCancelled() {
+		panic(loadCancelled{})
+	}
+
+	done := make(chan bool)
+	RunOnMainThread(func() {
+		defer func() { done <- true }()
+		last := Assets.setLoading(l.scene.SceneBase())
+		defer Assets.setLoading(last)
+		fn()
+	})
+	<-done
+}
+
+// updateSceneLoader is called by the main loop before every frame.
+func updateSceneLoader() {
+	if nextLoader != nil {
+		l := nextLoader
+		nextLoader = nil
+		l.start()
+	}
+
+	l := sceneLoader
+	if l == nil || !l.Done() {
+		return
+	}
+	sceneLoader = nil
+	if l.isCancelled() {
+		return
+	}
+	if err := l.Err(); err != nil {
+		LogScene.Error("Scene loading failed", "scene", l.scene.SceneBase().Name(), "err", err)
+		Assets.releaseScene(l.scene.SceneBase())
+		Assets.collect()
+		if l.OnError != nil {
+			l.OnError(l, err)
+		} else if l.loading != nil && l.previous != nil {
+			LoadScene(l.previous)
+		}
+		l.loaded.Fail(err)
+		return
+	}
+
+	switchScene(l.scene, false)
+	l.loaded.Resolve(l.scene)
+}
+
+// prepareScene runs Prepare on the main thread for scenes that are loaded with LoadScene.
+func prepareScene(scene Scene) {
+	if as, ok := scene.(AsyncScene); ok {
+		err := as.Prepare(&SceneLoader{scene: scene})
+		if err != nil {
+			LogScene.Error("Scene preparing failed", "scene", scene.SceneBase().Name(), "err", err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+type failingScene struct {
+	*SceneData
+}
+
+func (s *failingScene) New() Scene {
+	return &failingScene{SceneData: NewScene("FailingScene")}
+}
+
+func (s *failingScene) Load() {
+}
+
+func (s *failingScene) Prepare(loader *SceneLoader) error {
+	return errors.New("missing atlas")
+}
+
+// waitForLoader steps frames until the background load is over.
+func waitForLoader(h *Harness) {
+	for i := 0; i < 1000 && CurrentSceneLoader() != nil; i++ {
+		time.Sleep(time.Millisecond)
+		h.Step(1)
+	}
+}
+
+func TestSceneLoaderError(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+
+	LoadSceneAsync(&failingScene{}, &counterScene{})
+	waitForLoader(h)
+	if name := GetScene().SceneBase().Name(); name != "CoroutineScene" {
+		t.Errorf("%s is running after a failed load, expected the previous scene", name)
+	}
+
+	var failed error
+	loader := LoadSceneAsync(&failingScene{}, &counterScene{})
+	loader.OnError = func(l *SceneLoader, err error) { failed = err }
+	waitForLoader(h)
+	if failed == nil || loader.Err() != failed {
+		t.Errorf("OnError got %v", failed)
+	}
+	if name := GetScene().SceneBase().Name(); name != "CounterScene" {
+		t.Errorf("%s is running, OnError should decide what runs after a failed load", name)
+	}
+}
+
+// preparedScene waits for release (if it's set) in Prepare and then uploads a resource.
+type preparedScene struct {
+	*SceneData
+	release chan bool
+	uploads int
+	mem     *MemHandle
+}
+
+func (s *preparedScene) New() Scene {
+	return &preparedScene{SceneData: NewScene("PreparedScene"), release: s.release}
+}
+
+func (s *preparedScene) Load() {
+}
+
+func (s *preparedScene) Prepare(loader *SceneLoader) error {
+	loader.SetProgress(0.5)
+	if s.release != nil {
+		<-s.release
+	}
+	loader.Upload(func() {
+		s.uploads++
+		s.mem = Allocate(10)
+		Assets.Add(s.mem)
+	})
+	return nil
+}
+
+func TestSceneLoaderProgress(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+
+	release := make(chan bool)
+	loader := LoadSceneAsync(&preparedScene{release: release}, &counterScene{})
+	for i := 0; i < 1000 && loader.Progress() < 0.5; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	h.Step(1)
+	if loader.Done() || loader.Progress() != 0.5 {
+		t.Fatalf("progress is %f before Prepare returned", loader.Progress())
+	}
+	if name := GetScene().SceneBase().Name(); name != "CounterScene" {
+		t.Errorf("%s is running, expected the loading screen", name)
+	}
+
+	close(release)
+	waitForLoader(h)
+	s := loader.Scene().(*preparedScene)
+	if GetScene() != Scene(s) || loader.Progress() != 1 || loader.Err() != nil {
+		t.Fatalf("the loaded scene is not running, progress %f, err %v", loader.Progress(), loader.Err())
+	}
+	if s.uploads != 1 {
+		t.Errorf("Upload ran %d times", s.uploads)
+	}
+	if v, err := loader.Loaded().Result(); v != Scene(s) || err != nil {
+		t.Errorf("Loaded returned %v, %v", v, err)
+	}
+	if e := Assets.byRes[s.mem]; e == nil || !s.assets[e] {
+		t.Error("the uploaded resource doesn't belong to the loaded scene")
+	}
+}
+
+func TestSceneLoaderCancel(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	mem := NewMemorySink(10)
+	SetLogSinks(mem)
+	defer SetLogSinks(NewConsoleSink(os.Stdout))
+
+	release := make(chan bool)
+	first := LoadSceneAsync(&preparedScene{release: release}, &counterScene{})
+	second := LoadSceneAsync(&preparedScene{}, nil)
+	close(release)
+	waitForLoader(h)
+	for i := 0; i < 1000 && !first.Done(); i++ {
+		time.Sleep(time.Millisecond)
+	}
+
+	if first.Err() != ErrLoadCancelled || first.Scene().(*preparedScene).uploads != 0 {
+		t.Errorf("the cancelled load returned %v", first.Err())
+	}
+	if _, err := first.Loaded().Result(); err != ErrLoadCancelled {
+		t.Errorf("Loaded of the cancelled load failed with %v", err)
+	}
+	if GetScene() != second.Scene() {
+		t.Errorf("%s is running, expected the second scene", GetScene().SceneBase().Name())
+	}
+	for _, e := range mem.Entries() {
+		if e.Level >= LevelError {
+			t.Errorf("a cancelled load was logged: %s", e.String())
+		}
+	}
+}
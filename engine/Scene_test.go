@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vova616/garageEngine/engine/input"
+)
+
+func TestLoadSceneAdditive(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	main := GetScene()
+	main.SceneBase().Camera = NewCamera()
+
+	hud := LoadSceneAdditive(&counterScene{})
+	c := hud.(*counterScene).counter
+	h.Step(1)
+	if s := Scenes(); len(s) != 2 || s[0] != main || s[1] != hud {
+		t.Fatalf("the active scenes are %v", s)
+	}
+	if c.updates != 1 {
+		t.Errorf("the additive scene was updated %d times", c.updates)
+	}
+
+	//The HUD has no camera, it's drawn with the camera of the main scene.
+	setCurrentScene(hud)
+	camera := currentCamera()
+	setCurrentScene(nil)
+	if camera != main.SceneBase().Camera {
+		t.Error("a scene without a camera didn't get the camera of the main scene")
+	}
+
+	UnloadScene(hud)
+	h.Step(1)
+	if s := Scenes(); len(s) != 1 || s[0] != main {
+		t.Errorf("the active scenes are %v after UnloadScene", s)
+	}
+	if c.updates != 1 {
+		t.Error("an unloaded scene was updated")
+	}
+	UnloadScene(main)
+	if len(Scenes()) != 1 {
+		t.Error("the main scene was unloaded")
+	}
+}
+
+// keyWatcher saves if space is down in every Update.
+type keyWatcher struct {
+	BaseComponent
+	down bool
+}
+
+func (c *keyWatcher) Update() {
+	c.down = input.KeyDown(input.KeySpace)
+}
+
+func TestPushScene(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	below := h.Scene().(*coroutineScene).owner.AddComponent(&keyWatcher{BaseComponent: NewComponent()}).(*keyWatcher)
+	menu := PushScene(&counterScene{})
+	top := menu.(*counterScene).counter.GameObject().AddComponent(&keyWatcher{BaseComponent: NewComponent()}).(*keyWatcher)
+	pause := PushScene(&coroutineScene{})
+	if s := Scenes(); len(s) != 3 || s[1] != menu || s[2] != pause {
+		t.Fatalf("the active scenes are %v", s)
+	}
+
+	input.OnKey(input.KeySpace, input.Key_Press)
+	defer input.ClearInput()
+	h.Step(1)
+	if below.down || top.down {
+		t.Errorf("scenes under the pushed scene got the input, %v %v", below.down, top.down)
+	}
+
+	if PopScene() != pause {
+		t.Fatal("PopScene didn't pop the top scene")
+	}
+	h.Step(1)
+	if below.down || !top.down {
+		t.Errorf("the input went to the wrong scenes after a pop, %v %v", below.down, top.down)
+	}
+
+	PopScene()
+	h.Step(1)
+	if !below.down || len(Scenes()) != 1 {
+		t.Error("the main scene didn't get the input back")
+	}
+	if PopScene() != nil {
+		t.Error("the main scene was popped")
+	}
+}
+
+func TestBlockChars(t *testing.T) {
+	typed := ""
+	input.AddCharCallback(func(r rune) { typed += string(r) })
+
+	input.Block(true)
+	input.OnChar('a', input.Key_Press)
+	input.Block(false)
+	input.OnChar('b', input.Key_Press)
+	if typed != "b" {
+		t.Errorf("typed %q while the input was blocked", typed)
+	}
+}
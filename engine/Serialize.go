@@ -0,0 +1,342 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+)
+
+/*
+	Scene files are JSON, every game object keeps its transform, tag, components and children:
+
+	{
+		"Name": "Level1",
+		"GameObjects": [
+			{
+				"Name": "Box",
+				"Tag": "Wall",
+				"Position": {"X": 100, "Y": 50, "Z": 0},
+				"Components": [{"Type": "Physics", "Fields": {"Static": true, "Width": 50, "Height": 50}}],
+				"Children": []
+			}
+		]
+	}
+
+	Components are created by their registered name (see RegisterComponent). Their exported fields are saved
+	unless they are pointers, interfaces, funcs or channels or tagged with `json:"-"`.
+	Components that implement json.Marshaler and json.Unmarshaler save whatever they want.
+	Sprites save the path of their texture (TexturePath) and load it when they start, textures that were not loaded
+	from a file like atlases that were built in memory are not saved and the sprite comes back without a texture.
+*/
+
+type SceneFile struct {
+	Name        string
+	GameObjects []*GameObjectData
+}
+
+type GameObjectData struct {
+	Name     string
+	Tag      string `json:",omitempty"`
+	Layer    int    `json:",omitempty"`
+	Inactive bool   `json:",omitempty"`
+
+	Position Vector
+	Rotation Vector
+	Scale    Vector
+
+	Components []*ComponentData  `json:",omitempty"`
+	Children   []*GameObjectData `json:",omitempty"`
+}
+
+type ComponentData struct {
+	Type     string
+	Disabled bool            `json:",omitempty"`
+	Fields   json.RawMessage `json:",omitempty"`
+}
+
+// Serializer is implemented by components that need to update their saved fields before they are saved.
+type Serializer interface {
+	OnSerialize()
+}
+
+// Deserializer is implemented by components that need to update their internal state after their fields were loaded.
+type Deserializer interface {
+	OnDeserialize()
+}
+
+// FileScene is a scene that loads its game objects from a scene file.
+type FileScene struct {
+	*SceneData
+	Path string
+}
+
+func NewFileScene(path string) *FileScene {
+	return &FileScene{SceneData: NewScene(path), Path: path}
+}
+
+func (s *FileScene) New() Scene {
+	return NewFileScene(s.Path)
+}
+
+func (s *FileScene) Load() {
+	if err := LoadSceneFile(s, s.Path); err != nil {
+		LogScene.Error("Scene file loading failed", "path", s.Path, "err", err)
+	}
+}
+
+func SaveSceneFile(scene Scene, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return SaveScene(scene, f)
+}
+
+func LoadSceneFile(scene Scene, path string) error {
+	f, err := Files.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return DecodeScene(scene, f)
+}
+
+// SaveScene writes the root game objects of the scene and everything under them.
+func SaveScene(scene Scene, w io.Writer) error {
+	sd := scene.SceneBase()
+	file := SceneFile{Name: sd.name, GameObjects: make([]*GameObjectData, 0, len(sd.gameObjects))}
+	for _, g := range sd.gameObjects {
+		if g == nil || g.Transform().Parent() != nil || g.name == internalFPSName {
+			continue
+		}
+		data, err := EncodeGameObject(g)
+		if err != nil {
+			return err
+		}
+		file.GameObjects = append(file.GameObjects, data)
+	}
+
+	b, err := json.MarshalIndent(&file, "", "\t")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// DecodeScene reads a scene file and adds its game objects to the scene.
+// The first Camera found becomes the scene camera if the scene has none.
+func DecodeScene(scene Scene, r io.Reader) error {
+	var file SceneFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return err
+	}
+	sd := scene.SceneBase()
+	for _, data := range file.GameObjects {
+		g, err := DecodeGameObject(data)
+		if err != nil {
+			return err
+		}
+		sd.AddGameObject(g)
+	}
+	if sd.Camera == nil {
+		for _, g := range sd.gameObjects {
+			if c := findCamera(g); c != nil {
+				sd.SetCamera(c)
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func findCamera(g *GameObject) *Camera {
+	for _, c := range g.components {
+		if cam, ok := c.(*Camera); ok {
+			return cam
+		}
+	}
+	for _, t := range g.Transform().children {
+		if cam := findCamera(t.gameObject); cam != nil {
+			return cam
+		}
+	}
+	return nil
+}
+
+func EncodeGameObject(g *GameObject) (*GameObjectData, error) {
+	t := g.Transform()
+	data := &GameObjectData{
+		Name:     g.name,
+		Tag:      g.tag,
+		Layer:    g.layer,
+		Inactive: !g.active,
+		Position: t.Position(),
+		Rotation: t.Rotation(),
+		Scale:    t.Scale(),
+	}
+	for _, c := range g.components {
+		name, registered := ComponentName(c)
+		if !registered {
+			LogAssets.Warn("Component is not registered and will not be saved", "component", reflect.TypeOf(c), "gameObject", g.name)
+			continue
+		}
+		if s, ok := c.(Serializer); ok {
+			s.OnSerialize()
+		}
+		fields, err := EncodeComponent(c)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %v", g.name, name, err)
+		}
+		data.Components = append(data.Components, &ComponentData{Type: name, Disabled: !c.Enabled(), Fields: fields})
+	}
+	for _, child := range t.children {
+		if child.gameObject == nil || !child.gameObject.IsValid() {
+			continue
+		}
+		cd, err := EncodeGameObject(child.gameObject)
+		if err != nil {
+			return nil, err
+		}
+		data.Children = append(data.Children, cd)
+	}
+	return data, nil
+}
+
+func DecodeGameObject(data *GameObjectData) (*GameObject, error) {
+	g := NewGameObject(data.Name)
+	g.SetTag(data.Tag)
+	g.SetLayer(data.Layer)
+	g.SetActive(!data.Inactive)
+
+	t := g.Transform()
+	t.SetPosition(data.Position)
+	t.SetRotation(data.Rotation)
+	t.SetScale(data.Scale)
+
+	for _, cd := range data.Components {
+		c, err := NewComponentByName(cd.Type)
+		if err != nil {
+			return nil, err
+		}
+		if len(cd.Fields) > 0 {
+			if err = DecodeComponent(c, cd.Fields); err != nil {
+				return nil, fmt.Errorf("%s: %s: %v", data.Name, cd.Type, err)
+			}
+		}
+		if d, ok := c.(Deserializer); ok {
+			d.OnDeserialize()
+		}
+		c.SetEnabled(!cd.Disabled)
+		g.addComponent(c)
+		if cam, ok := c.(*Camera); ok {
+			cam.UpdateResolution()
+		}
+	}
+	addMissingComponents(g)
+
+	for _, cd := range data.Children {
+		child, err := DecodeGameObject(cd)
+		if err != nil {
+			return nil, err
+		}
+		//SetParent keeps the world transform, the saved one is local.
+		pos, rot, scale := child.Transform().Position(), child.Transform().Rotation(), child.Transform().Scale()
+		child.Transform().SetParent(t)
+		child.Transform().SetPosition(pos)
+		child.Transform().SetRotation(rot)
+		child.Transform().SetScale(scale)
+	}
+	return g, nil
+}
+
+// EncodeComponent returns the JSON of the component saved fields.
+func EncodeComponent(c Component) (json.RawMessage, error) {
+	if m, ok := c.(json.Marshaler); ok {
+		return m.MarshalJSON()
+	}
+	fields := make(map[string]interface{})
+	v := reflect.ValueOf(c).Elem()
+	for _, f := range serializedFields(v.Type()) {
+		fields[f.Name] = v.FieldByIndex(f.Index).Interface()
+	}
+	return json.Marshal(fields)
+}
+
+// DecodeComponent sets the component fields from JSON made by EncodeComponent, unknown fields are ignored.
+func DecodeComponent(c Component, data json.RawMessage) error {
+	if u, ok := c.(json.Unmarshaler); ok {
+		return u.UnmarshalJSON(data)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	v := reflect.ValueOf(c).Elem()
+	for _, f := range serializedFields(v.Type()) {
+		raw, exists := fields[f.Name]
+		if !exists {
+			continue
+		}
+		if err := json.Unmarshal(raw, v.FieldByIndex(f.Index).Addr().Interface()); err != nil {
+			return fmt.Errorf("field %s: %v", f.Name, err)
+		}
+	}
+	return nil
+}
+
+func serializedFields(typ reflect.Type) []reflect.StructField {
+	fields := make([]reflect.StructField, 0, typ.NumField())
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.PkgPath != "" || f.Anonymous || f.Tag.Get("json") == "-" {
+			continue
+		}
+		if !serializableType(f.Type, 0) {
+			continue
+		}
+		fields = append(fields, f)
+	}
+	return fields
+}
+
+var jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+func serializableType(typ reflect.Type, depth int) bool {
+	if depth > 8 {
+		return false
+	}
+	if typ.Implements(jsonMarshaler) || reflect.PtrTo(typ).Implements(jsonMarshaler) {
+		return typ.Kind() != reflect.Ptr && typ.Kind() != reflect.Interface
+	}
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice, reflect.Array:
+		return serializableType(typ.Elem(), depth+1)
+	case reflect.Map:
+		k := typ.Key().Kind()
+		if k != reflect.String && (k < reflect.Int || k > reflect.Uint64) {
+			return false
+		}
+		return serializableType(typ.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			f := typ.Field(i)
+			if f.PkgPath != "" || f.Tag.Get("json") == "-" {
+				continue
+			}
+			if !serializableType(f.Type, depth+1) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package engine
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSceneRoundTrip(t *testing.T) {
+	src := NewFileScene("Saved")
+	root := NewGameObject("Box")
+	root.SetTag("Wall")
+	root.SetLayer(3)
+	root.Transform().SetPositionf(100, 50)
+	root.Transform().SetRotationf(45)
+	root.Transform().SetScalef(2, 3)
+	ranged := root.AddComponent(ComponentInfoByName("rangedComponent").New()).(*rangedComponent)
+	ranged.Speed = 7
+	ranged.Name = "box"
+	root.AddComponent(NewPhysics(true, 50, 20))
+
+	child := NewGameObject("Eye")
+	child.Transform().SetParent2(root)
+	child.Transform().SetPositionf(5, 0)
+	sprite := child.AddComponent(NewSprite3(nil, AnimatedUV{NewUV(0, 0, 1, 1, 1)})).(*Sprite)
+	sprite.TexturePath = "data/eye.png"
+	child.AddComponent(NewCamera())
+	src.AddGameObject(root)
+
+	var buf bytes.Buffer
+	if err := SaveScene(src, &buf); err != nil {
+		t.Fatal(err)
+	}
+	dst := NewFileScene("Loaded")
+	if err := DecodeScene(dst, &buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(dst.gameObjects) != 1 {
+		t.Fatalf("loaded %d root objects, expected 1", len(dst.gameObjects))
+	}
+	g := dst.gameObjects[0]
+	if g.Name() != "Box" || g.Tag() != "Wall" || g.Layer() != 3 {
+		t.Errorf("loaded %s with tag %q on layer %d", g.Name(), g.Tag(), g.Layer())
+	}
+	if p, r, s := g.Transform().Position(), g.Transform().Rotation(), g.Transform().Scale(); p.X != 100 || p.Y != 50 || r.Z != 45 || s.X != 2 || s.Y != 3 {
+		t.Errorf("transform is %v %v %v", p, r, s)
+	}
+
+	if len(g.components) != 2 {
+		t.Fatalf("loaded %d components, expected 2", len(g.components))
+	}
+	c, ok := GetComponent[*rangedComponent](g)
+	if !ok || c.Speed != 7 || c.Name != "box" {
+		t.Errorf("fields are %+v", c)
+	}
+	if g.Physics == nil || !g.Physics.Body.IsStatic() || g.Physics.Box == nil || g.Physics.Box.Width != 50 || g.Physics.Box.Height != 20 {
+		t.Error("the Physics body was not loaded")
+	}
+
+	children := g.Transform().Children()
+	if len(children) != 1 || children[0].GameObject().Name() != "Eye" {
+		t.Fatalf("loaded %d children", len(children))
+	}
+	eye := children[0].GameObject()
+	if p := eye.Transform().Position(); p.X != 5 || p.Y != 0 {
+		t.Errorf("the child is at %v, expected its local position", p)
+	}
+	if eye.Sprite == nil || eye.Sprite.TexturePath != "data/eye.png" {
+		t.Error("the sprite texture path was not saved")
+	}
+	if cam, _ := GetComponent[*Camera](eye); dst.Camera == nil || dst.Camera != cam {
+		t.Error("the camera of the child should become the scene camera")
+	}
+}
+
+func TestSceneRoundTripInactive(t *testing.T) {
+	src := NewFileScene("Saved")
+	root := NewGameObject("Door")
+	root.SetActive(false)
+	child := NewGameObject("Handle")
+	child.Transform().SetParent2(root)
+	src.AddGameObject(root)
+
+	var buf bytes.Buffer
+	if err := SaveScene(src, &buf); err != nil {
+		t.Fatal(err)
+	}
+	dst := NewFileScene("Loaded")
+	if err := DecodeScene(dst, &buf); err != nil {
+		t.Fatal(err)
+	}
+	g := dst.gameObjects[0]
+	if g.ActiveSelf() {
+		t.Error("the inactive object was loaded active")
+	}
+	handle := g.Transform().Children()[0].GameObject()
+	if !handle.ActiveSelf() || handle.ActiveInHierarchy() {
+		t.Error("the child of an inactive object should be active itself but not in the hierarchy")
+	}
+}
@@ -143,11 +143,11 @@ func loadShadowShader() {
 
 	vrt.Compile()
 	if vrt.Get(gl.COMPILE_STATUS) != 1 {
-		println(vrt.GetInfoLog())
+		LogRender.Error("Shadow vertex shader compile failed", "log", vrt.GetInfoLog())
 	}
 	frg.Compile()
 	if frg.Get(gl.COMPILE_STATUS) != 1 {
-		println(frg.GetInfoLog())
+		LogRender.Error("Shadow fragment shader compile failed", "log", frg.GetInfoLog())
 	}
 
 	program.AttachShader(vrt)
@@ -173,11 +173,11 @@ func loadShadowCalc() {
 
 	vrt.Compile()
 	if vrt.Get(gl.COMPILE_STATUS) != 1 {
-		println(vrt.GetInfoLog())
+		LogRender.Error("Shadow vertex shader compile failed", "log", vrt.GetInfoLog())
 	}
 	frg.Compile()
 	if frg.Get(gl.COMPILE_STATUS) != 1 {
-		println(frg.GetInfoLog())
+		LogRender.Error("Shadow fragment shader compile failed", "log", frg.GetInfoLog())
 	}
 
 	program.AttachShader(vrt)
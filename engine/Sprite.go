@@ -7,7 +7,6 @@ import (
 	//"image/png"
 	//"image"
 	//"os"
-	"log"
 
 	"github.com/vova616/chipmunk/vect"
 	//"glfw"
@@ -35,6 +34,10 @@ type Sprite struct {
 
 	Color Color
 
+	//TexturePath is the file of the texture, scene files save it and a sprite without a texture loads it when it starts.
+	//Textures that were not loaded from a file (atlases built in memory, render targets) are not saved.
+	TexturePath string
+
 	align AlignType
 }
 
@@ -96,6 +99,18 @@ func (sp *Sprite) OnComponentBind(binded *GameObject) {
 	binded.Sprite = sp
 }
 
+func (sp *Sprite) OnSerialize() {
+	if sp.Texture != nil && sp.Texture.path != "" {
+		sp.TexturePath = sp.Texture.path
+	}
+}
+
+func (sp *Sprite) OnDeserialize() {
+	sp.startAnimation = 0
+	sp.endAnimation = len(sp.UVs)
+	sp.animation = 0
+}
+
 /*
 func (sp *Sprite) CreateVBO(uvs ...UV) {
 	l := len(uvs)
@@ -152,7 +167,14 @@ func (sp *Sprite) CreateVBO(uvs ...UV) {
 */
 
 func (sp *Sprite) Start() {
-
+	if sp.Texture == nil && sp.TexturePath != "" {
+		tex, err := Assets.Texture(sp.TexturePath)
+		if err != nil {
+			LogAssets.Error("Sprite texture loading failed", "path", sp.TexturePath, "err", err)
+			return
+		}
+		sp.Texture = tex
+	}
 }
 
 func (sp *Sprite) CurrentAnimationIndex() int {
@@ -186,10 +208,6 @@ func (sp *Sprite) Update() {
 		}
 	}
 	sp.UpdateShape()
-	if renders != 0 && Debug && 1 == 2 {
-		camera := GetScene().SceneBase().Camera
-		log.Println(renders, camera.Transform().WorldPosition(), camera.Transform().Matrix())
-	}
 	renders = 0
 }
 
@@ -299,7 +317,7 @@ func (sp *Sprite) Draw() {
 		v := Align(sp.align)
 		v.X *= currentUV.Ratio
 
-		camera := GetScene().SceneBase().Camera
+		camera := currentCamera()
 		view := camera.InvertedMatrix()
 		model := Identity()
 		model.Scale(currentUV.Ratio, 1, 1)
@@ -319,6 +337,7 @@ func (sp *Sprite) Draw() {
 		of.Uniform2f(currentUV.U1, currentUV.V1)
 
 		gl.DrawArrays(gl.QUADS, 0, 4)
+		CountDrawCall()
 
 		TextureMaterial.End(sp.GameObject())
 	}
@@ -327,7 +346,10 @@ func (sp *Sprite) Draw() {
 func (sp *Sprite) DrawScreen() {
 	if sp.Texture != nil && sp.Render {
 
-		camera := GetScene().SceneBase().Camera
+		camera := currentCamera()
+		if camera == nil {
+			return
+		}
 		pos := sp.Transform().WorldPosition()
 		scale := sp.Transform().WorldScale()
 
@@ -365,6 +387,7 @@ func (sp *Sprite) DrawScreen() {
 		ac.Uniform4f(sp.Color.R, sp.Color.G, sp.Color.B, sp.Color.A)
 
 		gl.DrawArrays(gl.QUADS, 0, 4)
+		CountDrawCall()
 
 		TextureMaterial.End(sp.GameObject())
 	}
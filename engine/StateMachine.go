@@ -0,0 +1,400 @@
+package engine
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+/*
+	A StateMachine is a component with named states, every state has optional OnEnter, OnUpdate and OnExit hooks:
+
+		sm := engine.NewStateMachine()
+		alive := sm.AddState("Alive")
+		alive.OnUpdate = func(sm *engine.StateMachine) { move() }
+		dead := sm.AddState("Dead")
+		dead.OnEnter = func(sm *engine.StateMachine) { explode() }
+		sm.AddState("Respawn").OnEnter = func(sm *engine.StateMachine) { respawn() }
+
+		sm.AddTransition("Alive", "Dead").On("die")
+		sm.AddTransition("Dead", "Respawn").When(func(sm *engine.StateMachine) bool { return sm.TimeInState() > 3 })
+		sm.AddTransition("Respawn", "Alive")
+		ship.AddComponent(sm)
+
+		sm.Fire("die")
+
+	States can have sub-states (state.AddState), entering a state enters its initial sub-state, the first one that was added
+	or the last active one if the state has History. A transition from a state is also taken from all of its sub-states.
+*/
+
+type State struct {
+	Name     string
+	OnEnter  func(sm *StateMachine)
+	OnUpdate func(sm *StateMachine)
+	OnExit   func(sm *StateMachine)
+	//History makes the state enter the sub-state that was active when it was left, instead of the initial one.
+	History bool
+
+	machine     *StateMachine
+	parent      *State
+	initial     *State
+	depth       int
+	transitions []*Transition
+}
+
+// AddState adds a sub-state, the first sub-state is the initial one.
+func (s *State) AddState(name string) *State {
+	child := s.machine.newState(name, s)
+	if s.initial == nil {
+		s.initial = child
+	}
+	return child
+}
+
+// SetInitial sets the sub-state that is entered with the state.
+func (s *State) SetInitial(name string) {
+	s.initial = s.machine.mustState(name)
+}
+
+func (s *State) Parent() *State {
+	return s.parent
+}
+
+// Transition moves the machine to To when its trigger is fired (if it has one) and its condition is true (if it has one).
+// A transition without a trigger is checked every Update.
+type Transition struct {
+	From, To *State
+	Trigger  string
+	Cond     func(sm *StateMachine) bool
+	//Reenter lets a transition from any state be taken while To is already active, it leaves and enters To again.
+	Reenter bool
+}
+
+// On makes the transition wait for Fire(trigger).
+func (t *Transition) On(trigger string) *Transition {
+	t.Trigger = trigger
+	return t
+}
+
+// When guards the transition with cond.
+func (t *Transition) When(cond func(sm *StateMachine) bool) *Transition {
+	t.Cond = cond
+	return t
+}
+
+// AllowReenter sets Reenter.
+func (t *Transition) AllowReenter() *Transition {
+	t.Reenter = true
+	return t
+}
+
+// TransitionRecord is a transition that happened, Trigger is empty for transitions of a condition or SetState.
+type TransitionRecord struct {
+	From, To string
+	Trigger  string
+	Time     time.Duration
+}
+
+type StateMachine struct {
+	BaseComponent
+	//HistorySize is the number of transitions Transitions keeps.
+	HistorySize int
+
+	states  map[string]*State
+	initial *State
+	//any holds the transitions that are taken from every state.
+	any []*Transition
+
+	current   *State
+	last      map[*State]*State
+	enteredAt time.Duration
+	records   []TransitionRecord
+
+	busy    bool
+	pending []func()
+}
+
+func NewStateMachine() *StateMachine {
+	return &StateMachine{BaseComponent: NewComponent(), HistorySize: 32, states: make(map[string]*State)}
+}
+
+// AddState adds a top level state, the first one is the initial state of the machine.
+func (sm *StateMachine) AddState(name string) *State {
+	s := sm.newState(name, nil)
+	if sm.initial == nil {
+		sm.initial = s
+	}
+	return s
+}
+
+func (sm *StateMachine) newState(name string, parent *State) *State {
+	if _, exists := sm.states[name]; exists {
+		panic(fmt.Sprintf("state %s already exists", name))
+	}
+	s := &State{Name: name, machine: sm, parent: parent}
+	if parent != nil {
+		s.depth = parent.depth + 1
+	}
+	sm.states[name] = s
+	return s
+}
+
+func (sm *StateMachine) mustState(name string) *State {
+	s, exists := sm.states[name]
+	if !exists {
+		panic(fmt.Sprintf("state %s doesn't exist", name))
+	}
+	return s
+}
+
+// State returns the state called name, nil if there is none.
+func (sm *StateMachine) State(name string) *State {
+	return sm.states[name]
+}
+
+// SetInitial sets the state the machine starts in.
+func (sm *StateMachine) SetInitial(name string) {
+	sm.initial = sm.mustState(name)
+}
+
+// AddTransition adds a transition between two states, from "" is any state except to and its sub-states.
+func (sm *StateMachine) AddTransition(from, to string) *Transition {
+	t := &Transition{To: sm.mustState(to)}
+	if from == "" {
+		sm.any = append(sm.any, t)
+		return t
+	}
+	t.From = sm.mustState(from)
+	t.From.transitions = append(t.From.transitions, t)
+	return t
+}
+
+// Current returns the active state that has no active sub-state, nil before the machine starts.
+func (sm *StateMachine) Current() *State {
+	return sm.current
+}
+
+func (sm *StateMachine) CurrentName() string {
+	if sm.current == nil {
+		return ""
+	}
+	return sm.current.Name
+}
+
+// IsIn returns true if the state called name or one of its sub-states is active.
+func (sm *StateMachine) IsIn(name string) bool {
+	for s := sm.current; s != nil; s = s.parent {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TimeInState returns the seconds of game time since the current state was entered.
+func (sm *StateMachine) TimeInState() float64 {
+	return (clock.Time() - sm.enteredAt).Seconds()
+}
+
+// Transitions returns the last HistorySize transitions, the oldest first.
+func (sm *StateMachine) Transitions() []TransitionRecord {
+	return sm.records
+}
+
+// DumpTransitions writes the last transitions, one in every line.
+func (sm *StateMachine) DumpTransitions(w io.Writer) error {
+	for _, r := range sm.records {
+		line := fmt.Sprintf("%v %s -> %s", r.Time, r.From, r.To)
+		if r.Trigger != "" {
+			line += " (" + r.Trigger + ")"
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sm *StateMachine) Start() {
+	sm.begin()
+}
+
+// begin enters the initial state, it's called by Start or by the first Fire or SetState before it.
+func (sm *StateMachine) begin() {
+	if sm.current != nil || sm.initial == nil {
+		return
+	}
+	sm.run(func() {
+		sm.enter(nil, sm.initial)
+		sm.record(nil, "")
+	})
+}
+
+// Update takes the first transition without a trigger whose condition is true and then calls OnUpdate
+// of the current state and its parents, the parents first.
+func (sm *StateMachine) Update() {
+	sm.begin()
+	if sm.current == nil {
+		return
+	}
+	sm.run(func() {
+		if t := sm.find(func(t *Transition) bool { return t.Trigger == "" }); t != nil {
+			sm.transition(t.To, "")
+		}
+	})
+	sm.run(func() {
+		sm.update(sm.current)
+	})
+}
+
+func (sm *StateMachine) update(s *State) {
+	if s == nil {
+		return
+	}
+	sm.update(s.parent)
+	if s.OnUpdate != nil {
+		s.OnUpdate(sm)
+	}
+}
+
+// Fire takes the first transition of trigger from the current state, the sub-states first.
+// Fire inside of a hook waits until the hook returns.
+func (sm *StateMachine) Fire(trigger string) {
+	sm.begin()
+	sm.run(func() {
+		if t := sm.find(func(t *Transition) bool { return t.Trigger == trigger }); t != nil {
+			sm.transition(t.To, trigger)
+		}
+	})
+}
+
+// SetState moves the machine to the state called name without a transition.
+func (sm *StateMachine) SetState(name string) {
+	to := sm.mustState(name)
+	sm.begin()
+	sm.run(func() {
+		sm.transition(to, "")
+	})
+}
+
+// run calls fn now, or after the hook that is running returns.
+func (sm *StateMachine) run(fn func()) {
+	if sm.busy {
+		sm.pending = append(sm.pending, fn)
+		return
+	}
+	sm.busy = true
+	fn()
+	for len(sm.pending) > 0 {
+		next := sm.pending[0]
+		sm.pending = sm.pending[1:]
+		next()
+	}
+	sm.busy = false
+}
+
+func (sm *StateMachine) find(match func(t *Transition) bool) *Transition {
+	for s := sm.current; s != nil; s = s.parent {
+		for _, t := range s.transitions {
+			if match(t) && (t.Cond == nil || t.Cond(sm)) {
+				return t
+			}
+		}
+	}
+	for _, t := range sm.any {
+		//Without Reenter a transition from any state is not taken while its state is active.
+		if !t.Reenter && isInside(sm.current, t.To) {
+			continue
+		}
+		if match(t) && (t.Cond == nil || t.Cond(sm)) {
+			return t
+		}
+	}
+	return nil
+}
+
+func (sm *StateMachine) transition(to *State, trigger string) {
+	from := sm.current
+	if from == nil {
+		return
+	}
+	//The states under the common parent of from and to are left, to itself is always left and entered again.
+	common := to.parent
+	for common != nil && !isInside(from, common) {
+		common = common.parent
+	}
+	for s := from; s != common; s = s.parent {
+		if s.OnExit != nil {
+			s.OnExit(sm)
+		}
+		if s.parent != nil {
+			if sm.last == nil {
+				sm.last = make(map[*State]*State)
+			}
+			sm.last[s.parent] = s
+		}
+	}
+	sm.enter(common, to)
+	sm.record(from, trigger)
+}
+
+// isInside returns true if s is parent or one of its sub-states.
+func isInside(s, parent *State) bool {
+	for ; s != nil; s = s.parent {
+		if s == parent {
+			return true
+		}
+	}
+	return false
+}
+
+// enter enters the states from under common down to to and then the initial (or history) sub-states of to.
+func (sm *StateMachine) enter(common, to *State) {
+	path := make([]*State, 0, to.depth+1)
+	for s := to; s != common; s = s.parent {
+		path = append(path, s)
+	}
+	s := to
+	for i := len(path) - 1; i >= 0; i-- {
+		s = path[i]
+		sm.current = s
+		if s.OnEnter != nil {
+			s.OnEnter(sm)
+		}
+	}
+	for s.initial != nil {
+		next := s.initial
+		if last := sm.last[s]; s.History && last != nil {
+			next = last
+		}
+		s = next
+		sm.current = s
+		if s.OnEnter != nil {
+			s.OnEnter(sm)
+		}
+	}
+	sm.enteredAt = clock.Time()
+}
+
+func (sm *StateMachine) record(from *State, trigger string) {
+	r := TransitionRecord{To: sm.current.Name, Trigger: trigger, Time: clock.Time()}
+	if from != nil {
+		r.From = from.Name
+	}
+	if sm.HistorySize <= 0 {
+		return
+	}
+	if len(sm.records) >= sm.HistorySize {
+		sm.records = append(sm.records[:0], sm.records[len(sm.records)-sm.HistorySize+1:]...)
+	}
+	sm.records = append(sm.records, r)
+}
+
+// Clone gives the copy its own current state and history, the states are shared.
+func (sm *StateMachine) Clone() {
+	sm.current = nil
+	sm.last = nil
+	sm.records = nil
+	sm.busy = false
+	sm.pending = nil
+}
@@ -0,0 +1,110 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStateMachine(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	g := h.Scene().(*coroutineScene).owner
+
+	var calls []string
+	hook := func(name string) func(sm *StateMachine) {
+		return func(sm *StateMachine) { calls = append(calls, name) }
+	}
+	updates := 0
+
+	sm := NewStateMachine()
+	alive := sm.AddState("Alive")
+	alive.History = true
+	alive.OnExit = hook("exit Alive")
+	idle := alive.AddState("Idle")
+	idle.OnEnter = hook("enter Idle")
+	moving := alive.AddState("Moving")
+	moving.OnEnter = hook("enter Moving")
+	moving.OnUpdate = func(sm *StateMachine) { updates++ }
+	dead := sm.AddState("Dead")
+	dead.OnEnter = func(sm *StateMachine) {
+		calls = append(calls, "enter Dead")
+		//Fired inside of a hook, it runs after OnEnter returns.
+		sm.Fire("explode")
+	}
+	sm.AddState("Exploded").OnEnter = hook("enter Exploded")
+
+	sm.AddTransition("Idle", "Moving").On("move")
+	sm.AddTransition("Alive", "Dead").On("die")
+	sm.AddTransition("Dead", "Exploded").On("explode")
+	sm.AddTransition("Exploded", "Alive").When(func(sm *StateMachine) bool { return sm.TimeInState() >= 0.25 })
+	g.AddComponent(sm)
+
+	h.Step(1)
+	if !sm.IsIn("Alive") || sm.CurrentName() != "Idle" {
+		t.Fatalf("the machine started in %s", sm.CurrentName())
+	}
+	sm.Fire("move")
+	h.Step(2)
+	if sm.CurrentName() != "Moving" || updates != 2 {
+		t.Fatalf("in %s with %d updates, expected Moving with 2", sm.CurrentName(), updates)
+	}
+
+	calls = nil
+	sm.Fire("die")
+	if sm.CurrentName() != "Exploded" {
+		t.Fatalf("in %s after die, expected Exploded", sm.CurrentName())
+	}
+	if got := strings.Join(calls, ", "); got != "exit Alive, enter Dead, enter Exploded" {
+		t.Errorf("hooks were called in this order: %s", got)
+	}
+	sm.Fire("move")
+	if sm.CurrentName() != "Exploded" {
+		t.Error("a trigger of another state was taken")
+	}
+
+	h.Step(10)
+	if sm.CurrentName() != "Exploded" {
+		t.Fatal("the condition was true too early")
+	}
+	h.Step(10)
+	if sm.CurrentName() != "Moving" {
+		t.Errorf("in %s, expected the history of Alive (Moving)", sm.CurrentName())
+	}
+
+	records := sm.Transitions()
+	if len(records) != 5 || records[0].To != "Idle" || records[3].Trigger != "explode" || records[4].From != "Exploded" {
+		t.Errorf("transitions are %+v", records)
+	}
+}
+
+func TestStateMachineAnyState(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	g := h.Scene().(*coroutineScene).owner
+
+	hp := 10
+	deaths := 0
+	sm := NewStateMachine()
+	sm.AddState("Alive")
+	sm.AddState("Dead").OnEnter = func(sm *StateMachine) { deaths++ }
+	sm.AddState("Respawn").OnEnter = func(sm *StateMachine) { hp = 10 }
+	sm.AddTransition("", "Dead").When(func(sm *StateMachine) bool { return hp <= 0 })
+	sm.AddTransition("", "Dead").On("kill").AllowReenter()
+	sm.AddTransition("Dead", "Respawn").When(func(sm *StateMachine) bool { return sm.TimeInState() > 0.25 })
+	sm.AddTransition("Respawn", "Alive")
+	g.AddComponent(sm)
+
+	h.Step(1)
+	hp = 0
+	h.Step(10)
+	if sm.CurrentName() != "Dead" || deaths != 1 {
+		t.Fatalf("in %s after dying %d times, expected Dead once", sm.CurrentName(), deaths)
+	}
+	sm.Fire("kill")
+	if deaths != 2 {
+		t.Error("a transition with Reenter didn't enter its active state again")
+	}
+	h.Step(30)
+	if sm.CurrentName() != "Alive" || hp != 10 || deaths != 2 {
+		t.Errorf("in %s with %d hp after dying %d times, expected Alive", sm.CurrentName(), hp, deaths)
+	}
+}
@@ -0,0 +1,189 @@
+package engine
+
+import (
+	"math"
+)
+
+/*
+	Steering moves a physics body with the steering behavior components of its game object:
+
+		g.AddComponent(engine.NewSteering(300, 50000))
+		g.AddComponent(engine.NewPursue(player, 1))
+		g.AddComponent(engine.NewSeparation(80, CookieTag, 2))
+		g.AddComponent(engine.NewObstacleAvoidance(200, 25, WallTag, 3))
+
+	Every behavior returns the change of velocity it wants, Steering adds them by their weights, turns the sum into
+	a force no stronger than MaxForce and applies it to the body in FixedUpdate, the speed of the body is kept under MaxSpeed.
+	Behaviors that are disabled are skipped, while none is enabled the body isn't steered or limited.
+	Adding a behavior adds Steering and Physics if the game object has none.
+*/
+
+// SteeringBehavior is a component that steers the Steering component of its game object.
+type SteeringBehavior interface {
+	Component
+	//Steer returns the velocity the behavior wants minus the current velocity.
+	Steer(s *Steering) Vector
+	SteeringWeight() float32
+}
+
+type Steering struct {
+	BaseComponent
+	//MaxSpeed is the speed the behaviors ask for and the body is kept under, MaxForce 0 doesn't limit the force.
+	MaxSpeed float32
+	MaxForce float32
+
+	force Vector
+}
+
+var steeringAgents []*Steering
+
+func NewSteering(maxSpeed, maxForce float32) *Steering {
+	return &Steering{BaseComponent: NewComponent(), MaxSpeed: maxSpeed, MaxForce: maxForce}
+}
+
+func (s *Steering) Start() {
+	steeringAgents = append(steeringAgents, s)
+}
+
+func (s *Steering) OnDestroy() {
+	for i, a := range steeringAgents {
+		if a == s {
+			copy(steeringAgents[i:], steeringAgents[i+1:])
+			steeringAgents[len(steeringAgents)-1] = nil
+			steeringAgents = steeringAgents[:len(steeringAgents)-1]
+			return
+		}
+	}
+}
+
+func (s *Steering) Position() Vector {
+	p := s.Transform().WorldPosition()
+	p.Z = 0
+	return p
+}
+
+func (s *Steering) Velocity() Vector {
+	return velocityOf(s.GameObject())
+}
+
+// Heading returns the direction of the velocity, or the up direction of the transform if the body doesn't move.
+func (s *Steering) Heading() Vector {
+	v := s.Velocity()
+	if v.Length() > 0.0001 {
+		return v.Normalized()
+	}
+	up := s.Transform().DirectionTransform(Up)
+	up.Z = 0
+	return up.Normalized()
+}
+
+// Force returns the force that was applied in the last physics step.
+func (s *Steering) Force() Vector {
+	return s.force
+}
+
+// Neighbors returns the other enabled Steering game objects with tag ("" for any tag) that are closer than radius.
+func (s *Steering) Neighbors(radius float32, tag string) []*Steering {
+	pos := s.Position()
+	var neighbors []*Steering
+	for _, a := range steeringAgents {
+		if a == s || !a.Enabled() || !a.gameObject.ActiveInHierarchy() || (tag != "" && !a.gameObject.CompareTag(tag)) {
+			continue
+		}
+		if p := a.Position(); pos.Distance(p) < radius {
+			neighbors = append(neighbors, a)
+		}
+	}
+	return neighbors
+}
+
+func (s *Steering) FixedUpdate() {
+	ph := s.gameObject.Physics
+	if ph == nil || ph.Body.IsStatic() {
+		return
+	}
+	total := Zero
+	steering := false
+	for _, c := range s.gameObject.components {
+		b, ok := c.(SteeringBehavior)
+		if !ok || !c.Enabled() {
+			continue
+		}
+		steering = true
+		v := b.Steer(s)
+		total = total.Add(v.Mul2(b.SteeringWeight()))
+	}
+	s.force = Zero
+	//Without behaviors the body is left alone, it can be pushed faster than MaxSpeed.
+	if !steering {
+		return
+	}
+	total.Z = 0
+
+	//The force that changes the velocity by total in one step.
+	force := total.Mul2(float32(ph.Body.Mass()) / float32(stepTime))
+	force = truncate(force, s.MaxForce)
+	ph.Body.AddForce(force.X, force.Y)
+	s.force = force
+
+	if v := s.Velocity(); s.MaxSpeed > 0 && v.Length() > s.MaxSpeed {
+		v = truncate(v, s.MaxSpeed)
+		ph.Body.SetVelocity(v.X, v.Y)
+	}
+}
+
+// Clone drops the force of the original, the clone is added to the agents on Start.
+func (s *Steering) Clone() {
+	s.force = Zero
+}
+
+func velocityOf(g *GameObject) Vector {
+	if g == nil || g.Physics == nil {
+		return Zero
+	}
+	v := g.Physics.Body.Velocity()
+	return Vector{float32(v.X), float32(v.Y), 0}
+}
+
+// truncate returns v with a length of max at most, max 0 doesn't limit.
+func truncate(v Vector, max float32) Vector {
+	if l := v.Length(); max > 0 && l > max {
+		return v.Mul2(max / l)
+	}
+	return v
+}
+
+func dot(a, b Vector) float32 {
+	return a.X*b.X + a.Y*b.Y
+}
+
+// seekVelocity returns the change of velocity that moves s toward target at full speed.
+func seekVelocity(s *Steering, target Vector) Vector {
+	dir := target.Sub(s.Position())
+	dir.Z = 0
+	desired := dir.Normalized()
+	desired = desired.Mul2(s.MaxSpeed)
+	return desired.Sub(s.Velocity())
+}
+
+// fleeVelocity returns the change of velocity that moves s away from target at full speed.
+func fleeVelocity(s *Steering, target Vector) Vector {
+	pos := s.Position()
+	dir := pos.Sub(target)
+	dir.Z = 0
+	desired := dir.Normalized()
+	desired = desired.Mul2(s.MaxSpeed)
+	return desired.Sub(s.Velocity())
+}
+
+// predict returns where target will be when s can reach it.
+func predict(s *Steering, target *GameObject) Vector {
+	pos := target.Transform().WorldPosition()
+	pos.Z = 0
+	if s.MaxSpeed <= 0 {
+		return pos
+	}
+	t := pos.Distance(s.Position()) / s.MaxSpeed
+	v := velocityOf(target)
+	return pos.Add(v.Mul2(float32(math.Min(float64(t), 2))))
+}
@@ -0,0 +1,330 @@
+package engine
+
+import (
+	"math"
+	"math/rand"
+)
+
+// targetPosition returns the position of target, or position if there is no target or it was destroyed.
+func targetPosition(target *GameObject, position Vector) Vector {
+	if target != nil && target.IsValid() {
+		position = target.Transform().WorldPosition()
+	}
+	position.Z = 0
+	return position
+}
+
+// Seek moves toward Target, or toward Position if Target is nil.
+type Seek struct {
+	BaseComponent
+	Target   *GameObject
+	Position Vector
+	Weight   float32
+}
+
+func NewSeek(target *GameObject, weight float32) *Seek {
+	return &Seek{BaseComponent: NewComponent(), Target: target, Weight: weight}
+}
+
+func (b *Seek) SteeringWeight() float32 {
+	return b.Weight
+}
+
+func (b *Seek) Steer(s *Steering) Vector {
+	return seekVelocity(s, targetPosition(b.Target, b.Position))
+}
+
+// Flee moves away from Target (or Position) while it's closer than PanicDistance, 0 flees at any distance.
+type Flee struct {
+	BaseComponent
+	Target        *GameObject
+	Position      Vector
+	PanicDistance float32
+	Weight        float32
+}
+
+func NewFlee(target *GameObject, panicDistance, weight float32) *Flee {
+	return &Flee{BaseComponent: NewComponent(), Target: target, PanicDistance: panicDistance, Weight: weight}
+}
+
+func (b *Flee) SteeringWeight() float32 {
+	return b.Weight
+}
+
+func (b *Flee) Steer(s *Steering) Vector {
+	target := targetPosition(b.Target, b.Position)
+	if pos := s.Position(); b.PanicDistance > 0 && pos.Distance(target) > b.PanicDistance {
+		return Zero
+	}
+	return fleeVelocity(s, target)
+}
+
+// Arrive moves toward Target (or Position) and slows down inside of SlowingRadius to stop on it.
+type Arrive struct {
+	BaseComponent
+	Target        *GameObject
+	Position      Vector
+	SlowingRadius float32
+	Weight        float32
+}
+
+func NewArrive(target *GameObject, slowingRadius, weight float32) *Arrive {
+	return &Arrive{BaseComponent: NewComponent(), Target: target, SlowingRadius: slowingRadius, Weight: weight}
+}
+
+func (b *Arrive) SteeringWeight() float32 {
+	return b.Weight
+}
+
+func (b *Arrive) Steer(s *Steering) Vector {
+	target := targetPosition(b.Target, b.Position)
+	dir := target.Sub(s.Position())
+	dir.Z = 0
+	dist := dir.Length()
+	speed := s.MaxSpeed
+	if b.SlowingRadius > 0 && dist < b.SlowingRadius {
+		speed *= dist / b.SlowingRadius
+	}
+	desired := Zero
+	if dist > 0.0001 {
+		desired = dir.Mul2(speed / dist)
+	}
+	return desired.Sub(s.Velocity())
+}
+
+// Wander moves to a point on a circle of Radius that is Distance ahead, the point moves by up to Jitter radians every second.
+type Wander struct {
+	BaseComponent
+	Radius   float32
+	Distance float32
+	Jitter   float32
+	Weight   float32
+
+	angle float32
+}
+
+func NewWander(radius, distance, jitter, weight float32) *Wander {
+	return &Wander{BaseComponent: NewComponent(), Radius: radius, Distance: distance, Jitter: jitter, Weight: weight, angle: rand.Float32() * 2 * math.Pi}
+}
+
+func (b *Wander) SteeringWeight() float32 {
+	return b.Weight
+}
+
+func (b *Wander) Steer(s *Steering) Vector {
+	b.angle += (rand.Float32()*2 - 1) * b.Jitter * float32(stepTime)
+	pos := s.Position()
+	heading := s.Heading()
+	center := pos.Add(heading.Mul2(b.Distance))
+	sin, cos := math.Sincos(float64(b.angle))
+	offset := Vector{float32(cos) * b.Radius, float32(sin) * b.Radius, 0}
+	return seekVelocity(s, center.Add(offset))
+}
+
+// Pursue moves to where Target will be, it uses the velocity of the target's physics body.
+type Pursue struct {
+	BaseComponent
+	Target *GameObject
+	Weight float32
+}
+
+func NewPursue(target *GameObject, weight float32) *Pursue {
+	return &Pursue{BaseComponent: NewComponent(), Target: target, Weight: weight}
+}
+
+func (b *Pursue) SteeringWeight() float32 {
+	return b.Weight
+}
+
+func (b *Pursue) Steer(s *Steering) Vector {
+	if b.Target == nil || !b.Target.IsValid() {
+		return Zero
+	}
+	return seekVelocity(s, predict(s, b.Target))
+}
+
+// Evade moves away from where Target will be while it's closer than PanicDistance, 0 evades at any distance.
+type Evade struct {
+	BaseComponent
+	Target        *GameObject
+	PanicDistance float32
+	Weight        float32
+}
+
+func NewEvade(target *GameObject, panicDistance, weight float32) *Evade {
+	return &Evade{BaseComponent: NewComponent(), Target: target, PanicDistance: panicDistance, Weight: weight}
+}
+
+func (b *Evade) SteeringWeight() float32 {
+	return b.Weight
+}
+
+func (b *Evade) Steer(s *Steering) Vector {
+	if b.Target == nil || !b.Target.IsValid() {
+		return Zero
+	}
+	target := targetPosition(b.Target, Zero)
+	if pos := s.Position(); b.PanicDistance > 0 && pos.Distance(target) > b.PanicDistance {
+		return Zero
+	}
+	return fleeVelocity(s, predict(s, b.Target))
+}
+
+// Separation moves away from the Steering neighbors with Tag ("" for all) that are closer than Radius, the closest push the most.
+type Separation struct {
+	BaseComponent
+	Radius float32
+	Tag    string
+	Weight float32
+}
+
+func NewSeparation(radius float32, tag string, weight float32) *Separation {
+	return &Separation{BaseComponent: NewComponent(), Radius: radius, Tag: tag, Weight: weight}
+}
+
+func (b *Separation) SteeringWeight() float32 {
+	return b.Weight
+}
+
+func (b *Separation) Steer(s *Steering) Vector {
+	pos := s.Position()
+	push := Zero
+	for _, n := range s.Neighbors(b.Radius, b.Tag) {
+		away := pos.Sub(n.Position())
+		dist := away.Length()
+		if dist < 0.0001 {
+			continue
+		}
+		push = push.Add(away.Mul2((1 - dist/b.Radius) / dist))
+	}
+	if push.Length() < 0.0001 {
+		return Zero
+	}
+	desired := push.Normalized()
+	desired = desired.Mul2(s.MaxSpeed)
+	return desired.Sub(s.Velocity())
+}
+
+// Alignment moves in the average direction of the Steering neighbors with Tag ("" for all) that are closer than Radius.
+type Alignment struct {
+	BaseComponent
+	Radius float32
+	Tag    string
+	Weight float32
+}
+
+func NewAlignment(radius float32, tag string, weight float32) *Alignment {
+	return &Alignment{BaseComponent: NewComponent(), Radius: radius, Tag: tag, Weight: weight}
+}
+
+func (b *Alignment) SteeringWeight() float32 {
+	return b.Weight
+}
+
+func (b *Alignment) Steer(s *Steering) Vector {
+	heading := Zero
+	for _, n := range s.Neighbors(b.Radius, b.Tag) {
+		h := n.Heading()
+		heading = heading.Add(h)
+	}
+	if heading.Length() < 0.0001 {
+		return Zero
+	}
+	desired := heading.Normalized()
+	desired = desired.Mul2(s.MaxSpeed)
+	return desired.Sub(s.Velocity())
+}
+
+// Cohesion moves toward the center of the Steering neighbors with Tag ("" for all) that are closer than Radius.
+type Cohesion struct {
+	BaseComponent
+	Radius float32
+	Tag    string
+	Weight float32
+}
+
+func NewCohesion(radius float32, tag string, weight float32) *Cohesion {
+	return &Cohesion{BaseComponent: NewComponent(), Radius: radius, Tag: tag, Weight: weight}
+}
+
+func (b *Cohesion) SteeringWeight() float32 {
+	return b.Weight
+}
+
+func (b *Cohesion) Steer(s *Steering) Vector {
+	neighbors := s.Neighbors(b.Radius, b.Tag)
+	if len(neighbors) == 0 {
+		return Zero
+	}
+	center := Zero
+	for _, n := range neighbors {
+		center = center.Add(n.Position())
+	}
+	return seekVelocity(s, center.Mul2(1/float32(len(neighbors))))
+}
+
+// ObstacleAvoidance turns away from the game objects with Tag that are up to Distance ahead.
+// Obstacles are circles as big as the larger side of their world scale, Radius is the radius of this game object.
+type ObstacleAvoidance struct {
+	BaseComponent
+	Distance float32
+	Radius   float32
+	Tag      string
+	Weight   float32
+}
+
+func NewObstacleAvoidance(distance, radius float32, tag string, weight float32) *ObstacleAvoidance {
+	return &ObstacleAvoidance{BaseComponent: NewComponent(), Distance: distance, Radius: radius, Tag: tag, Weight: weight}
+}
+
+func (b *ObstacleAvoidance) SteeringWeight() float32 {
+	return b.Weight
+}
+
+func (b *ObstacleAvoidance) Steer(s *Steering) Vector {
+	if b.Tag == "" || b.Distance <= 0 {
+		return Zero
+	}
+	pos := s.Position()
+	heading := s.Heading()
+
+	found := false
+	closest := float32(math.MaxFloat32)
+	var side float32
+	for _, o := range FindAllWithTag(b.Tag) {
+		if o == s.gameObject {
+			continue
+		}
+		c := targetPosition(o, Zero)
+		scale := o.Transform().WorldScale()
+		r := float32(math.Max(float64(scale.X), float64(scale.Y)))/2 + b.Radius
+		to := c.Sub(pos)
+		ahead := dot(to, heading)
+		if ahead <= 0 || ahead-r > b.Distance {
+			continue
+		}
+		//cross is the distance of the obstacle from the line of the heading, positive on the left.
+		cross := heading.X*to.Y - heading.Y*to.X
+		if float32(math.Abs(float64(cross))) >= r {
+			continue
+		}
+		if ahead < closest {
+			found = true
+			closest = ahead
+			side = cross
+		}
+	}
+	if !found {
+		return Zero
+	}
+
+	away := Vector{heading.Y, -heading.X, 0}
+	if side < 0 {
+		away = Vector{-heading.Y, heading.X, 0}
+	}
+	strength := 1 - closest/b.Distance
+	if strength < 0.1 {
+		strength = 0.1
+	}
+	return away.Mul2(s.MaxSpeed * strength)
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSteeringSeek(t *testing.T) {
+	h := NewHarness(&coroutineScene{}, time.Second/60)
+	g := h.Scene().(*coroutineScene).owner
+
+	seek := g.AddComponent(NewSeek(nil, 1)).(*Seek)
+	seek.Position = Vector{1000, 0, 0}
+	s, ok := GetComponent[*Steering](g)
+	if !ok || g.Physics == nil {
+		t.Fatal("Seek should add Steering and Physics")
+	}
+	s.MaxSpeed = 100
+
+	h.Step(60)
+	pos := s.Position()
+	if pos.X < 50 || pos.X > 101 {
+		t.Errorf("moved to %v in a second at a speed of 100", pos)
+	}
+	if v := s.Velocity(); v.Length() > s.MaxSpeed+0.01 {
+		t.Errorf("speed %f is over MaxSpeed", v.Length())
+	}
+
+	//A far target is out of the panic distance.
+	flee := g.AddComponent(NewFlee(nil, 10, 1)).(*Flee)
+	flee.Position = Vector{-1000, 0, 0}
+	if v := flee.Steer(s); v != Zero {
+		t.Errorf("Flee steered by %v outside of its panic distance", v)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if v := truncate(Vector{3, 4, 0}, 1); v.Length() < 0.99 || v.Length() > 1.01 {
+		t.Errorf("truncated to a length of %f, expected 1", v.Length())
+	}
+	if v := truncate(Vector{3, 4, 0}, 0); v != (Vector{3, 4, 0}) {
+		t.Errorf("max 0 changed the vector to %v", v)
+	}
+}
@@ -8,8 +8,9 @@ import (
 	"image/gif"
 	_ "image/jpeg"
 	_ "image/png"
+	"io"
+	"io/fs"
 	//"log"
-	"os"
 	"reflect"
 	"unsafe"
 )
@@ -114,6 +115,10 @@ type Texture struct {
 	target         gl.GLenum
 	width          int
 	height         int
+	//path is the file the texture was loaded from, it is reloaded when hot reload is enabled.
+	path string
+	//mipmaps is true after BuildMipmaps, a reload builds them again.
+	mipmaps bool
 }
 
 func (t *Texture) GLTexture() gl.Texture {
@@ -133,15 +138,49 @@ func LoadTexture(path string) (tex *Texture, err error) {
 	if e != nil {
 		return nil, e
 	}
+	tex, err = LoadTextureFromImage(img)
+	if tex != nil {
+		tex.path = path
+		watchFile(path, tex, tex.reload)
+	}
+	return tex, err
+}
+
+// LoadTextureFS loads a texture from fsys, textures from Files are hot reloaded.
+func LoadTextureFS(fsys fs.FS, name string) (tex *Texture, err error) {
+	if fsys == fs.FS(Files) {
+		return LoadTexture(name)
+	}
+	img, e := LoadImageFS(fsys, name)
+	if e != nil {
+		return nil, e
+	}
+	return LoadTextureFromImage(img)
+}
+
+func LoadTextureReader(r io.Reader) (tex *Texture, err error) {
+	img, e := LoadImageReader(r)
+	if e != nil {
+		return nil, e
+	}
 	return LoadTextureFromImage(img)
 }
 
 func LoadImage(path string) (img image.Image, err error) {
-	f, e := os.Open(path)
+	return LoadImageFS(Files, path)
+}
+
+func LoadImageFS(fsys fs.FS, name string) (img image.Image, err error) {
+	f, e := fsys.Open(name)
 	if e != nil {
 		return nil, e
 	}
-	img, _, e = image.Decode(f)
+	defer f.Close()
+	return LoadImageReader(f)
+}
+
+func LoadImageReader(r io.Reader) (img image.Image, err error) {
+	img, _, e := image.Decode(r)
 	if e != nil {
 		return nil, e
 	}
@@ -149,11 +188,20 @@ func LoadImage(path string) (img image.Image, err error) {
 }
 
 func LoadGIF(path string) (imgs []image.Image, err error) {
-	f, e := os.Open(path)
+	return LoadGIFFS(Files, path)
+}
+
+func LoadGIFFS(fsys fs.FS, name string) (imgs []image.Image, err error) {
+	f, e := fsys.Open(name)
 	if e != nil {
 		return nil, e
 	}
-	GIF, e := gif.DecodeAll(f)
+	defer f.Close()
+	return LoadGIFReader(f)
+}
+
+func LoadGIFReader(r io.Reader) (imgs []image.Image, err error) {
+	GIF, e := gif.DecodeAll(r)
 	if e != nil {
 		return nil, e
 	}
@@ -185,82 +233,94 @@ func LoadTextureFromImage(image image.Image) (tex *Texture, err error) {
 	if e != nil {
 		return nil, nil
 	}
+	data, release, e := imageData(image)
+	if e != nil {
+		return nil, e
+	}
+	defer release()
+
+	return NewTexture2(data, image.Bounds().Dx(), image.Bounds().Dy(), target, internalFormat, typ, format), nil
+}
 
+// imageData converts the pixels of the image to the layout ColorModelToGLTypes returns, release has to be called after the upload.
+func imageData(image image.Image) (data interface{}, release func(), err error) {
 	//
 	w := image.Bounds().Dx()
 	h := image.Bounds().Dy()
 	model := image.ColorModel()
-	var data []byte
+	var pix []byte
+	release = func() {}
 
 	switch model.(type) {
 	case color.Palette:
 		memHandle := Allocate(4 * h * w)
-		data = memHandle.Bytes()
-		defer memHandle.Release()
+		pix = memHandle.Bytes()
+		release = memHandle.Release
 		for x := 0; x < w; x++ {
 			for y := 0; y < h; y++ {
 				offset := (x + (y * w)) * 4
 				r, g, b, a := image.At(x, y).RGBA()
-				data[offset] = byte(r / 257)
-				data[offset+1] = byte(g / 257)
-				data[offset+2] = byte(b / 257)
-				data[offset+3] = byte(a / 257)
+				pix[offset] = byte(r / 257)
+				pix[offset+1] = byte(g / 257)
+				pix[offset+2] = byte(b / 257)
+				pix[offset+3] = byte(a / 257)
 			}
 		}
+		return pix, release, nil
 	}
 
 	switch model {
 	case color.YCbCrModel:
 		memHandle := Allocate(3 * h * w)
-		data = memHandle.Bytes()
-		defer memHandle.Release()
+		pix = memHandle.Bytes()
+		release = memHandle.Release
 		for x := 0; x < w; x++ {
 			for y := 0; y < h; y++ {
 				offset := (x + y*w) * 3
 				r, g, b, _ := image.At(x, y).RGBA()
-				data[offset] = byte(r / 257)
-				data[offset+1] = byte(g / 257)
-				data[offset+2] = byte(b / 257)
+				pix[offset] = byte(r / 257)
+				pix[offset+1] = byte(g / 257)
+				pix[offset+2] = byte(b / 257)
 			}
 		}
 	case color.RGBAModel, color.NRGBAModel:
 		memHandle := Allocate(4 * h * w)
-		data = memHandle.Bytes()
-		defer memHandle.Release()
+		pix = memHandle.Bytes()
+		release = memHandle.Release
 		for x := 0; x < w; x++ {
 			for y := 0; y < h; y++ {
 				offset := (x + (y * w)) * 4
 				r, g, b, a := image.At(x, y).RGBA()
-				data[offset] = byte(r / 257)
-				data[offset+1] = byte(g / 257)
-				data[offset+2] = byte(b / 257)
-				data[offset+3] = byte(a / 257)
+				pix[offset] = byte(r / 257)
+				pix[offset+1] = byte(g / 257)
+				pix[offset+2] = byte(b / 257)
+				pix[offset+3] = byte(a / 257)
 			}
 		}
 	case color.RGBA64Model, color.NRGBA64Model:
 		memHandle := Allocate(4 * h * w)
-		data = memHandle.Bytes()
-		defer memHandle.Release()
+		pix = memHandle.Bytes()
+		release = memHandle.Release
 		for x := 0; x < w; x++ {
 			for y := 0; y < h; y++ {
 				offset := (x + y*w) * 4
 				r, g, b, a := image.At(x, y).RGBA()
-				data[offset] = byte(r / 257)
-				data[offset+1] = byte(g / 257)
-				data[offset+2] = byte(b / 257)
-				data[offset+3] = byte(a / 257)
+				pix[offset] = byte(r / 257)
+				pix[offset+1] = byte(g / 257)
+				pix[offset+2] = byte(b / 257)
+				pix[offset+3] = byte(a / 257)
 			}
 		}
 	default:
 		m, e := CustomColorModels[model]
 		if e {
-			return NewTexture2(m.Model.Data(), image.Bounds().Dx(), image.Bounds().Dy(), target, internalFormat, typ, format), nil
+			return m.Model.Data(), release, nil
 		} else {
-			return nil, errors.New("unsupported format")
+			return nil, release, errors.New("unsupported format")
 		}
 	}
 
-	return NewTexture2(data, image.Bounds().Dx(), image.Bounds().Dy(), target, internalFormat, typ, format), nil
+	return pix, release, nil
 }
 
 func ColorModelToGLTypes(model color.Model) (internalFormat int, typ gl.GLenum, format gl.GLenum, target gl.GLenum, err error) {
@@ -316,7 +376,7 @@ func NewTexture2(data interface{}, width int, height int, target gl.GLenum, inte
 	a.Bind(target)
 	gl.TexImage2D(target, 0, internalFormat, width, height, 0, typ, format, data)
 
-	t := &Texture{a, false, data, format, typ, internalFormat, target, width, height}
+	t := &Texture{a, false, data, format, typ, internalFormat, target, width, height, "", false}
 
 	t.SetWraping(WrapS, ClampToEdge)
 	t.SetWraping(WrapT, ClampToEdge)
@@ -330,7 +390,7 @@ func NewTexture2(data interface{}, width int, height int, target gl.GLenum, inte
 	t.data = nil
 	data = nil
 
-	ResourceManager.Add(t)
+	Assets.Add(t)
 
 	return t
 }
@@ -344,13 +404,13 @@ func NewTextureEmpty(width int, height int, model color.Model) *Texture {
 	a.Bind(target)
 	gl.TexImage2D(target, 0, internalFormat, width, height, 0, typ, format, nil)
 
-	t := &Texture{a, false, nil, format, typ, internalFormat, target, width, height}
+	t := &Texture{a, false, nil, format, typ, internalFormat, target, width, height, "", false}
 
 	t.SetWraping(WrapS, ClampToEdge)
 	t.SetWraping(WrapT, ClampToEdge)
 	t.SetFiltering(Nearest, Nearest)
 
-	ResourceManager.Add(t)
+	Assets.Add(t)
 
 	return t
 }
@@ -387,6 +447,7 @@ func (t *Texture) SetWraping(wrapType WrapType, wrap Wrap) {
 func (t *Texture) BuildMipmaps() {
 	t.Bind()
 	gl.GenerateMipmap(t.target)
+	t.mipmaps = true
 }
 
 func (t *Texture) PixelSize() int {
@@ -483,7 +544,53 @@ func (t *Texture) PreloadRender() {
 	gl.End()
 }
 
+// reload uploads the file of the texture again to the same OpenGL texture, so its parameters stay.
+func (t *Texture) reload() error {
+	img, err := LoadImage(t.path)
+	if err != nil {
+		return err
+	}
+	internalFormat, typ, format, target, err := ColorModelToGLTypes(img.ColorModel())
+	if err != nil {
+		return err
+	}
+	data, release, err := imageData(img)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	t.handle.Bind(target)
+	lastBindedTexture = t.handle
+	gl.TexImage2D(target, 0, internalFormat, img.Bounds().Dx(), img.Bounds().Dy(), 0, typ, format, data)
+	if t.mipmaps {
+		gl.GenerateMipmap(target)
+	}
+	t.internalFormat, t.typ, t.format, t.target = internalFormat, typ, format, target
+	t.width, t.height = img.Bounds().Dx(), img.Bounds().Dy()
+	return nil
+}
+
+// MemorySize returns the bytes of the texture on the GPU.
+func (t *Texture) MemorySize() int64 {
+	if t == nil {
+		return 0
+	}
+	channels := int64(1)
+	switch t.typ {
+	case gl.RGBA:
+		channels = 4
+	case gl.RGB:
+		channels = 3
+	}
+	if t.format == gl.UNSIGNED_SHORT {
+		channels *= 2
+	}
+	return int64(t.width*t.height) * channels
+}
+
 func (t *Texture) Release() {
+	unwatchFiles(t)
 	t.data = nil
 	if t.handle != 0 {
 		t.handle.Delete()
@@ -308,6 +308,8 @@ func (t *Transform) Translatef(x, y float32) {
 }
 
 func (t *Transform) SetParent(parent *Transform) {
+	g := t.gameObject
+	wasActive := g != nil && g.valid && g.ActiveInHierarchy()
 	if t.parent != nil {
 		for i, c := range t.parent.children {
 			if t == c {
@@ -324,6 +326,12 @@ func (t *Transform) SetParent(parent *Transform) {
 	if parent != nil {
 		parent.children = append(parent.children, t)
 	}
+	if g != nil && g.valid && g.active && wasActive != g.ActiveInHierarchy() {
+		g.activeChanged(!wasActive)
+	}
+	if g != nil && g.valid {
+		reindex(g)
+	}
 }
 
 func (t *Transform) SetParent2(g *GameObject) {
@@ -386,13 +394,13 @@ func (t *Transform) Matrix() Matrix {
 	return *t.matrix
 }
 
-func (t *Transform) clone(parent *GameObject) *Transform {
+func (t *Transform) clone(parent *GameObject, refs cloneRefs) *Transform {
 	tn := NewTransform(parent)
 	tn.position = t.position
 	tn.rotation = t.rotation
 	tn.scale = t.scale
 	for _, c := range t.children {
-		c.gameObject.Clone().transform.SetParent(tn)
+		c.gameObject.clone(refs).transform.SetParent(tn)
 	}
 	return tn
 }
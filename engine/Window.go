@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"github.com/go-gl/glfw"
+	"github.com/vova616/garageEngine/engine/input"
+	"github.com/vova616/gl"
+)
+
+// Window is the platform layer the game loop talks to.
+// The default one opens a GLFW window with an OpenGL context, the headless one
+// does nothing so scenes can run on machines without a display (tests, servers).
+type Window interface {
+	Open() error
+	Opened() bool
+	Clear()
+	SwapBuffers()
+	SetTitle(title string)
+	Close()
+	// Renders tells the engine if Draw routines should be called.
+	Renders() bool
+}
+
+var (
+	// Set Headless to true before StartEngine to run without GLFW and OpenGL.
+	Headless = false
+
+	window Window
+)
+
+type glfwWindow struct {
+}
+
+func (w *glfwWindow) Open() error {
+	var err error
+	if err = glfw.Init(); err != nil {
+		return err
+	}
+	LogRender.Info("GLFW initialized")
+
+	glfw.OpenWindowHint(glfw.Accelerated, 1)
+
+	if err = glfw.OpenWindow(Width, Height, 8, 8, 8, 8, 8, 8, glfw.Windowed); err != nil {
+		return err
+	}
+
+	if VSync {
+		glfw.SetSwapInterval(1)
+	} else {
+		glfw.SetSwapInterval(0)
+	}
+	glfw.SetWindowTitle(windowTitle)
+	glfw.SetWindowSizeCallback(onResize)
+	glfw.SetKeyCallback(input.OnKey)
+	glfw.SetCharCallback(input.OnChar)
+	glfw.SetMouseButtonCallback(input.ButtonPress)
+	input.MousePosition = glfw.MousePos
+
+	if err = initGL(); err != nil {
+		return err
+	}
+	LogRender.Info("OpenGL initialized")
+
+	TextureMaterial = NewBasicMaterial(spriteVertexShader, spriteFragmentShader)
+	err = TextureMaterial.Load()
+	if err != nil {
+		LogRender.Error("Material loading failed", "material", "TextureMaterial", "err", err)
+	}
+
+	SDFMaterial = NewBasicMaterial(sdfVertexShader, sdfFragmentShader)
+	err = SDFMaterial.Load()
+	if err != nil {
+		LogRender.Error("Material loading failed", "material", "SDFMaterial", "err", err)
+	}
+
+	internalMaterial = NewBasicMaterial(spriteVertexShader, spriteFragmentShader)
+	err = internalMaterial.Load()
+	if err != nil {
+		LogRender.Error("Material loading failed", "material", "internalMaterial", "err", err)
+	}
+
+	initDefaultPlane()
+	return nil
+}
+
+func (w *glfwWindow) Opened() bool {
+	return glfw.WindowParam(glfw.Opened) == 1
+}
+
+func (w *glfwWindow) Clear() {
+	gl.ClearColor(0, 0, 0, 0)
+	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
+	gl.LoadIdentity()
+}
+
+func (w *glfwWindow) SwapBuffers() {
+	glfw.SwapBuffers()
+}
+
+func (w *glfwWindow) SetTitle(title string) {
+	glfw.SetWindowTitle(title)
+}
+
+func (w *glfwWindow) Close() {
+	glfw.Terminate()
+}
+
+func (w *glfwWindow) Renders() bool {
+	return true
+}
+
+// headlessWindow is a fake window, it stays open until Close is called.
+// The mouse is always at (0,0) and nothing is rendered.
+type headlessWindow struct {
+	opened bool
+}
+
+func (w *headlessWindow) Open() error {
+	w.opened = true
+	input.MousePosition = func() (int, int) { return 0, 0 }
+	return nil
+}
+
+func (w *headlessWindow) Opened() bool {
+	return w.opened
+}
+
+func (w *headlessWindow) Clear() {
+
+}
+
+func (w *headlessWindow) SwapBuffers() {
+
+}
+
+func (w *headlessWindow) SetTitle(title string) {
+
+}
+
+func (w *headlessWindow) Close() {
+	w.opened = false
+}
+
+func (w *headlessWindow) Renders() bool {
+	return false
+}
+
+func newWindow() Window {
+	if Headless {
+		return &headlessWindow{}
+	}
+	return &glfwWindow{}
+}
@@ -0,0 +1,62 @@
+package components
+
+import (
+	"fmt"
+	"github.com/vova616/garageEngine/engine"
+)
+
+// ProfilerOverlay draws the last frame of engine.DefaultProfiler, a line for every top scope and counter.
+// Put it on a game object under the GUI camera, the lines go down from its position.
+type ProfilerOverlay struct {
+	engine.BaseComponent
+	Font       *engine.Font
+	Interval   float32
+	LineHeight float32
+
+	lines    []*UIText
+	timeleft float64
+}
+
+func NewProfilerOverlay(font *engine.Font) *ProfilerOverlay {
+	return &ProfilerOverlay{BaseComponent: engine.NewComponent(), Font: font, Interval: 0.5, LineHeight: 1.2}
+}
+
+func (po *ProfilerOverlay) Update() {
+	po.timeleft -= engine.UnscaledDeltaTime()
+	if po.timeleft > 0 {
+		return
+	}
+	po.timeleft = float64(po.Interval)
+
+	f := engine.DefaultProfiler.LastFrame()
+	if f == nil || po.Font == nil {
+		return
+	}
+	texts := []string{fmt.Sprintf("Frame: %v", f.Duration)}
+	for _, s := range f.Samples {
+		if s.Depth == 0 {
+			texts = append(texts, fmt.Sprintf("%s: %v", s.Name, s.Duration))
+		}
+	}
+	for _, name := range f.CounterNames() {
+		texts = append(texts, fmt.Sprintf("%s: %d", name, f.Counters[name]))
+	}
+
+	for len(po.lines) < len(texts) {
+		line := engine.NewGameObject("ProfilerLine")
+		line.Transform().SetParent2(po.GameObject())
+		line.Transform().SetPositionf(0, -po.LineHeight*float32(len(po.lines)))
+		line.Transform().SetScalef(1, 1)
+		txt := NewUIText(po.Font, "")
+		txt.SetAlign(engine.AlignLeft)
+		line.AddComponent(txt)
+		po.lines = append(po.lines, txt)
+	}
+	for i, txt := range po.lines {
+		if i < len(texts) {
+			txt.SetString(texts[i])
+		} else {
+			txt.SetString("")
+		}
+	}
+}
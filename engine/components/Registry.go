@@ -0,0 +1,14 @@
+package components
+
+import (
+	"github.com/vova616/garageEngine/engine"
+)
+
+func init() {
+	engine.RegisterComponent("Collider", func() engine.Component { return NewCollider() })
+	engine.RegisterComponent("Controller", func() engine.Component { return NewController() })
+	engine.RegisterComponent("SmoothFollow", func() engine.Component { return NewSmoothFollow(nil, 1, 0) })
+
+	//The camera follows the target after it moved.
+	engine.SetExecutionOrder(&SmoothFollow{}, 100)
+}
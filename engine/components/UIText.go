@@ -219,6 +219,12 @@ func (ui *UIText) SetWritable(b bool) {
 }
 
 func (ui *UIText) charCallback(rn rune) {
+	//Chars are typed between frames, a scene under a pushed scene doesn't get them.
+	if g := ui.GameObject(); g != nil {
+		if sd := g.Scene(); sd != nil && !sd.Input() {
+			return
+		}
+	}
 	if ui.focused && ui.writeable {
 		ui.text += string(rn)
 		ui.updateText = true
@@ -365,5 +371,6 @@ func (ui *UIText) Draw() {
 	color.Uniform4f(ui.Color.R, ui.Color.G, ui.Color.B, ui.Color.A)
 
 	gl.DrawArrays(gl.QUADS, 0, ui.vertexCount)
+	engine.CountDrawCall()
 
 }
@@ -27,6 +27,9 @@ type Tween struct {
 
 	reverse bool
 	Format  string
+
+	// Unscaled tweens keep running while the game is paused or slowed down (UI).
+	Unscaled bool
 }
 
 func (this *Tween) SetFunc(typeFunc TypeFunc) {
@@ -37,8 +40,15 @@ func (this *Tween) Progress() float32 {
 	return this.progress
 }
 
+func (t *Tween) now() time.Time {
+	if t.Unscaled {
+		return engine.UnscaledGameTime()
+	}
+	return engine.GameTime()
+}
+
 func (t *Tween) updateProgress() bool {
-	delta := engine.GameTime().Sub(t.startTime)
+	delta := t.now().Sub(t.startTime)
 	if t.reverse {
 		t.progress = 1 - float32(float64(delta)/float64(t.Time))
 	} else {
@@ -58,7 +58,7 @@ func Create(t *Tween) *Tween {
 	if t.Loop == nil {
 		t.Loop = None
 	}
-	t.startTime = engine.GameTime()
+	t.startTime = t.now()
 	t.progress = 0
 	return t
 }
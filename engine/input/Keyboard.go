@@ -7,6 +7,8 @@ var (
 	mouseState = make(map[int]byte)
 
 	charCallbacks = []CharCallback{}
+
+	blocked = false
 )
 
 const (
@@ -25,6 +27,9 @@ func OnKey(key, state int) {
 }
 
 func OnChar(key, state int) {
+	if blocked {
+		return
+	}
 	for _, callback := range charCallbacks {
 		callback(rune(key))
 	}
@@ -52,14 +57,23 @@ func ClearInput() {
 	}
 }
 
+// Block makes every key and mouse query report nothing pressed and drops typed chars until Block(false) is called.
+func Block(b bool) {
+	blocked = b
+}
+
+func Blocked() bool {
+	return blocked
+}
+
 func KeyDown(key int) bool {
-	return keyState[key]&pressed != 0
+	return !blocked && keyState[key]&pressed != 0
 }
 
 func KeyUp(key int) bool {
-	return keyState[key]&pressed == 0
+	return blocked || keyState[key]&pressed == 0
 }
 
 func KeyPress(key int) bool {
-	return keyState[key]&wasPressed != 0
+	return !blocked && keyState[key]&wasPressed != 0
 }
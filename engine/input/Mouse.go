@@ -14,13 +14,13 @@ func ButtonPress(btn, state int) {
 }
 
 func MouseDown(key int) bool {
-	return mouseState[key]&pressed != 0
+	return !blocked && mouseState[key]&pressed != 0
 }
 
 func MouseUp(key int) bool {
-	return mouseState[key]&pressed == 0
+	return blocked || mouseState[key]&pressed == 0
 }
 
 func MousePress(key int) bool {
-	return mouseState[key]&wasPressed != 0
+	return !blocked && mouseState[key]&wasPressed != 0
 }
@@ -0,0 +1,311 @@
+// Package logging writes leveled entries with key/value fields to the log sinks, every subsystem has its own category:
+//
+//	log := logging.NewLogger("ai")
+//	log.Debug("Target changed", "target", target.Name())
+//
+// Entries below the level of their category are dropped, the console sink is the only sink by default.
+// The engine logs through it too (engine.LogAssets, engine.LogNetwork...), so packages that don't import the engine,
+// like the game servers, share its sinks and levels.
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type Level int
+
+const (
+	LevelDebug = Level(iota)
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+var levelNames = [...]string{"DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(levelNames) {
+		return "LEVEL" + strconv.Itoa(int(l))
+	}
+	return levelNames[l]
+}
+
+// ParseLevel parses a level name like "debug" or "WARN".
+func ParseLevel(name string) (Level, error) {
+	for i, n := range levelNames {
+		if strings.EqualFold(n, name) {
+			return Level(i), nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q", name)
+}
+
+type Entry struct {
+	Time     time.Time
+	Level    Level
+	Category string
+	Message  string
+	//Fields are key/value pairs.
+	Fields []interface{}
+}
+
+// String formats the entry as one line, "15:04:05.000 INFO  [scene] Scene loaded name=GameScene".
+func (e *Entry) String() string {
+	b := make([]byte, 0, 64+len(e.Message))
+	b = e.Time.AppendFormat(b, "15:04:05.000")
+	b = append(b, ' ')
+	b = append(b, fmt.Sprintf("%-5s", e.Level)...)
+	b = append(b, " ["...)
+	b = append(b, e.Category...)
+	b = append(b, "] "...)
+	b = append(b, e.Message...)
+	for i := 0; i < len(e.Fields); i += 2 {
+		b = append(b, ' ')
+		b = append(b, fmt.Sprint(e.Fields[i])...)
+		b = append(b, '=')
+		if i+1 < len(e.Fields) {
+			b = appendValue(b, e.Fields[i+1])
+		}
+	}
+	return string(b)
+}
+
+func appendValue(b []byte, v interface{}) []byte {
+	s := fmt.Sprint(v)
+	if strings.Contains(s, "\n") {
+		//Stacks and other multi line values go under the entry.
+		return append(append(b, '\n'), s...)
+	}
+	if strings.ContainsAny(s, " =\"") {
+		return strconv.AppendQuote(b, s)
+	}
+	return append(b, s...)
+}
+
+// Sink receives every entry that passed the level check, sinks are called under the log lock.
+type Sink interface {
+	WriteEntry(e *Entry)
+}
+
+var (
+	lock           sync.Mutex
+	sinks          = []Sink{NewConsoleSink(os.Stdout)}
+	level          = LevelInfo
+	categoryLevels = make(map[string]Level)
+)
+
+func AddSink(sink Sink) {
+	lock.Lock()
+	sinks = append(sinks, sink)
+	lock.Unlock()
+}
+
+func RemoveSink(sink Sink) {
+	lock.Lock()
+	for i, s := range sinks {
+		if s == sink {
+			sinks = append(sinks[:i:i], sinks[i+1:]...)
+			break
+		}
+	}
+	lock.Unlock()
+}
+
+// SetSinks replaces all of the sinks.
+func SetSinks(s ...Sink) {
+	lock.Lock()
+	sinks = s
+	lock.Unlock()
+}
+
+// SetLevel sets the level of the categories that don't have their own.
+func SetLevel(l Level) {
+	lock.Lock()
+	level = l
+	lock.Unlock()
+}
+
+func SetCategoryLevel(category string, l Level) {
+	lock.Lock()
+	categoryLevels[category] = l
+	lock.Unlock()
+}
+
+type Logger struct {
+	category string
+}
+
+func NewLogger(category string) *Logger {
+	return &Logger{category}
+}
+
+func (l *Logger) Category() string {
+	return l.category
+}
+
+// Enabled returns false if entries of the level are dropped.
+func (l *Logger) Enabled(level Level) bool {
+	lock.Lock()
+	defer lock.Unlock()
+	return l.enabled(level)
+}
+
+func (l *Logger) enabled(lvl Level) bool {
+	min, exists := categoryLevels[l.category]
+	if !exists {
+		min = level
+	}
+	return lvl >= min
+}
+
+func (l *Logger) Log(level Level, msg string, fields ...interface{}) {
+	lock.Lock()
+	defer lock.Unlock()
+	if !l.enabled(level) {
+		return
+	}
+	e := &Entry{Time: time.Now(), Level: level, Category: l.category, Message: msg, Fields: fields}
+	for _, s := range sinks {
+		s.WriteEntry(e)
+	}
+}
+
+func (l *Logger) Debug(msg string, fields ...interface{}) {
+	l.Log(LevelDebug, msg, fields...)
+}
+
+func (l *Logger) Info(msg string, fields ...interface{}) {
+	l.Log(LevelInfo, msg, fields...)
+}
+
+func (l *Logger) Warn(msg string, fields ...interface{}) {
+	l.Log(LevelWarn, msg, fields...)
+}
+
+func (l *Logger) Error(msg string, fields ...interface{}) {
+	l.Log(LevelError, msg, fields...)
+}
+
+// ConsoleSink writes entries as lines.
+type ConsoleSink struct {
+	w io.Writer
+}
+
+func NewConsoleSink(w io.Writer) *ConsoleSink {
+	return &ConsoleSink{w}
+}
+
+func (s *ConsoleSink) WriteEntry(e *Entry) {
+	io.WriteString(s.w, e.String()+"\n")
+}
+
+// RotatingFileSink writes entries to a file, when the file gets bigger than MaxSize it is renamed to path.1
+// (path.1 to path.2 and so on) and a new file is started, only Keep old files are kept.
+type RotatingFileSink struct {
+	path    string
+	file    *os.File
+	size    int64
+	MaxSize int64
+	Keep    int
+}
+
+func NewRotatingFileSink(path string, maxSize int64, keep int) (*RotatingFileSink, error) {
+	s := &RotatingFileSink{path: path, MaxSize: maxSize, Keep: keep}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s.file = f
+	if info, err := f.Stat(); err == nil {
+		s.size = info.Size()
+	}
+	return s, nil
+}
+
+func (s *RotatingFileSink) WriteEntry(e *Entry) {
+	if s.file == nil {
+		return
+	}
+	line := e.String() + "\n"
+	if s.MaxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.MaxSize {
+		s.rotate()
+		if s.file == nil {
+			return
+		}
+	}
+	n, _ := s.file.WriteString(line)
+	s.size += int64(n)
+}
+
+func (s *RotatingFileSink) rotate() {
+	s.file.Close()
+	s.file = nil
+	if s.Keep > 0 {
+		os.Remove(s.path + "." + strconv.Itoa(s.Keep))
+		for i := s.Keep - 1; i > 0; i-- {
+			os.Rename(s.path+"."+strconv.Itoa(i), s.path+"."+strconv.Itoa(i+1))
+		}
+		os.Rename(s.path, s.path+".1")
+	}
+	f, err := os.Create(s.path)
+	if err != nil {
+		return
+	}
+	s.file = f
+	s.size = 0
+}
+
+func (s *RotatingFileSink) Close() error {
+	lock.Lock()
+	defer lock.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// MemorySink keeps the last entries, for an in-game console.
+type MemorySink struct {
+	entries []Entry
+	next    int
+	count   int
+}
+
+func NewMemorySink(size int) *MemorySink {
+	if size < 1 {
+		size = 1
+	}
+	return &MemorySink{entries: make([]Entry, size)}
+}
+
+func (s *MemorySink) WriteEntry(e *Entry) {
+	s.entries[s.next] = *e
+	s.next = (s.next + 1) % len(s.entries)
+	if s.count < len(s.entries) {
+		s.count++
+	}
+}
+
+// Entries returns a copy of the kept entries from the oldest to the newest.
+func (s *MemorySink) Entries() []Entry {
+	lock.Lock()
+	defer lock.Unlock()
+	entries := make([]Entry, 0, s.count)
+	first := s.next - s.count
+	if first < 0 {
+		first += len(s.entries)
+	}
+	for i := 0; i < s.count; i++ {
+		entries = append(entries, s.entries[(first+i)%len(s.entries)])
+	}
+	return entries
+}
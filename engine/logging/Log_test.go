@@ -0,0 +1,51 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLevelsAndSinks(t *testing.T) {
+	mem := NewMemorySink(2)
+	SetSinks(mem)
+	defer SetSinks(NewConsoleSink(os.Stdout))
+	SetCategoryLevel("test", LevelWarn)
+	defer delete(categoryLevels, "test")
+
+	l := NewLogger("test")
+	l.Info("dropped")
+	l.Warn("first")
+	l.Error("second", "path", "a b", "n", 1)
+	l.Error("third")
+
+	entries := mem.Entries()
+	if len(entries) != 2 || entries[0].Message != "second" || entries[1].Message != "third" {
+		t.Fatalf("expected the last two entries, got %v", entries)
+	}
+	if s := entries[0].String(); !strings.HasSuffix(s, `ERROR [test] second path="a b" n=1`) {
+		t.Errorf("entry is formatted as %q", s)
+	}
+}
+
+func TestRotatingFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log.txt")
+	sink, err := NewRotatingFileSink(path, 60, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	SetSinks(sink)
+	defer SetSinks(NewConsoleSink(os.Stdout))
+
+	for i := 0; i < 3; i++ {
+		NewLogger("engine").Error("a line that is long enough to rotate")
+	}
+	sink.Close()
+	if _, err := os.Stat(path + ".1"); err != nil {
+		t.Error("the old file was not kept")
+	}
+	if _, err := os.Stat(path + ".2"); err == nil {
+		t.Error("only one old file should be kept")
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"flag"
-	"fmt"
 	"github.com/vova616/garageEngine/engine"
 	"github.com/vova616/garageEngine/networkOnline"
 	"github.com/vova616/garageEngine/spaceCookies/game"
@@ -14,37 +13,114 @@ import (
 	"os"
 	//"runtime"
 	"runtime/pprof"
+	"strings"
 	//"time"
 )
 
 var cpuprofile = flag.String("p", "", "write cpu profile to file")
 var memprofile = flag.String("m", "", "write mem profile to file")
+var logLevel = flag.String("log", "info", "log level: debug, info, warn or error")
+var configPath = flag.String("config", "./config.json", "engine config file, flags override it")
+var gameName = flag.String("game", "spaceCookies", "game to launch: spaceCookies, zumbies or networkOnline")
+var sceneName = flag.String("scene", "", "start scene of the game, its first scene by default")
+var mode = flag.String("mode", "both", "both runs the game with a local server, client only the game and server only the server")
+var packs = flag.String("packs", "", "comma separated zip files or directories mounted over the data, the last one wins")
+
+// gameInfo is a game the launcher can start, scenes[0] is the default start scene.
+type gameInfo struct {
+	scenes    []string
+	scene     func(name string) engine.Scene
+	hasServer bool
+}
+
+var games = map[string]gameInfo{
+	"spaceCookies": {[]string{"login", "game"}, func(name string) engine.Scene {
+		if name == "game" {
+			return game.GameSceneGeneral
+		}
+		return login.LoginSceneGeneral
+	}, true},
+	"zumbies":       {[]string{"game"}, func(string) engine.Scene { return zumbies.GameSceneGeneral }, false},
+	"networkOnline": {[]string{"game"}, func(string) engine.Scene { return networkOnline.GameSceneGeneral }, false},
+}
+
+var config = engine.DefaultConfig()
+
+func init() {
+	config.BindFlags(flag.CommandLine)
+}
 
 func main() {
 	flag.Parse()
+
+	level, err := engine.ParseLogLevel(*logLevel)
+	if err != nil {
+		engine.LogEngine.Warn("Invalid log level", "err", err)
+	}
+	engine.SetLogLevel(level)
+	if file, err := engine.NewRotatingFileSink("./log.txt", 5<<20, 3); err == nil {
+		engine.AddLogSink(file)
+		defer file.Close()
+	} else {
+		engine.LogEngine.Error("Log file creation failed", "err", err)
+	}
+
+	if err := config.LoadFile(*configPath, flag.CommandLine); err != nil && !os.IsNotExist(err) {
+		engine.LogEngine.Error("Config loading failed", "path", *configPath, "err", err)
+	}
+	engine.ApplyConfig(config)
+
+	if *packs != "" {
+		for _, pack := range strings.Split(*packs, ",") {
+			if _, err := engine.Files.MountPack("", pack); err != nil {
+				engine.LogAssets.Error("Pack mounting failed", "pack", pack, "err", err)
+			}
+		}
+	}
+
+	g, exists := games[*gameName]
+	if !exists {
+		engine.LogEngine.Error("Unknown game", "game", *gameName)
+		return
+	}
+	if *sceneName == "" {
+		*sceneName = g.scenes[0]
+	}
+	if !contains(g.scenes, *sceneName) {
+		engine.LogEngine.Error("Unknown scene", "game", *gameName, "scene", *sceneName, "scenes", g.scenes)
+		return
+	}
+	switch *mode {
+	case "both", "client":
+	case "server":
+		if !g.hasServer {
+			engine.LogEngine.Error("The game has no server", "game", *gameName)
+			return
+		}
+		server.StartServer(engine.CurrentConfig().ServerAddr)
+		return
+	default:
+		engine.LogEngine.Error("Unknown mode", "mode", *mode)
+		return
+	}
+
 	if *cpuprofile != "" {
 		f, err := os.Create(*cpuprofile)
 		if err != nil {
-			fmt.Errorf("%s\n", err)
+			engine.LogEngine.Error("CPU profile creation failed", "err", err)
 		}
 		pprof.StartCPUProfile(f)
 
 		defer pprof.StopCPUProfile()
 	}
 
-	file, _ := os.Create("./log.txt")
-	os.Stdout = file
-	os.Stderr = file
-	os.Stdin = file
-	defer file.Close()
-
-	go Start()
+	go Start(g.scene(*sceneName), g.hasServer && *mode == "both")
 	engine.Terminated()
 
 	if *memprofile != "" {
 		f, err := os.Create(*memprofile)
 		if err != nil {
-			fmt.Errorf("%s\n", err)
+			engine.LogEngine.Error("Memory profile creation failed", "err", err)
 		}
 		pprof.WriteHeapProfile(f)
 		f.Close()
@@ -52,30 +128,29 @@ func main() {
 	}
 }
 
-func Start() {
-	defer func() {
-		if p := recover(); p != nil {
-			fmt.Println(p, engine.PanicPath())
-		}
-
-		engine.Terminate()
-	}()
+func Start(scene engine.Scene, localServer bool) {
+	defer engine.Terminate()
+	defer engine.LogPanic()
 	engine.StartEngine()
-	_ = game.GameSceneGeneral
-	_ = networkOnline.GameSceneGeneral
-	_ = login.LoginSceneGeneral
-	_ = zumbies.GameSceneGeneral
 
-	/*
-		Running local server.
-	*/
-	go server.StartServer()
+	if localServer {
+		go server.StartServer(engine.CurrentConfig().ServerAddr)
+	}
 
-	engine.LoadScene(login.LoginSceneGeneral)
+	engine.LoadScene(scene)
 	for engine.MainLoop() {
 
 	}
 }
 
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
 // 	
 // 
@@ -0,0 +1,18 @@
+//go:build embed
+// +build embed
+
+package main
+
+import (
+	"embed"
+	"github.com/vova616/garageEngine/engine"
+)
+
+// Building with -tags embed puts the data directory in the binary, packs from -packs are still mounted over it.
+//
+//go:embed data
+var data embed.FS
+
+func init() {
+	engine.Files.Mount("", data)
+}
@@ -30,12 +30,12 @@ var (
 )
 
 func (s *GameScene) Load() {
-	ArialFont, err := engine.NewFont("./data/Fonts/arial.ttf", 48)
+	ArialFont, err := engine.Assets.Font("./data/Fonts/arial.ttf", 48)
 	if err != nil {
 		panic(err)
 	}
 
-	ArialFont2, err := engine.NewFont("./data/Fonts/arial.ttf", 24)
+	ArialFont2, err := engine.Assets.Font("./data/Fonts/arial.ttf", 24)
 	if err != nil {
 		panic(err)
 	}
@@ -84,11 +84,11 @@ func (s *GameScene) Load() {
 	atlas := engine.NewManagedAtlas(512, 512)
 	e := atlas.LoadGroup("./data/fire")
 	if e != nil {
-		fmt.Println(e)
+		engine.LogAssets.Error("Atlas loading failed", "path", "./data/fire", "err", e)
 	}
 	e = atlas.LoadGroup("./data/Charecter")
 	if e != nil {
-		fmt.Println(e)
+		engine.LogAssets.Error("Atlas loading failed", "path", "./data/Charecter", "err", e)
 	}
 	err, rectID := atlas.LoadImage("./data/rect.png")
 	if err != nil {
@@ -120,8 +120,8 @@ func (s *GameScene) Load() {
 	f.Transform().SetPositionf(25, 300)
 	f.Transform().SetParent2(Layer1)
 
-	box, _ = engine.LoadTexture("./data/rect.png")
-	cir, _ = engine.LoadTexture("./data/circle.png")
+	box, _ = engine.Assets.Texture("./data/rect.png")
+	cir, _ = engine.Assets.Texture("./data/circle.png")
 	cir.BuildMipmaps()
 	cir.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
 
@@ -234,7 +234,7 @@ func (s *GameScene) Load() {
 	s.AddGameObject(Layer3)
 	//s.AddGameObject(shadowShader)
 
-	fmt.Println("Scene loaded")
+	engine.LogScene.Info("Scene loaded", "scene", s.Name())
 }
 
 func (s *GameScene) New() engine.Scene {
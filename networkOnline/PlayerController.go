@@ -40,7 +40,7 @@ func (sp *PlayerController) OnCollisionEnter(arbiter engine.Arbiter) bool {
 	return true
 }
 func (sp *PlayerController) OnCollisionExit(arbiter engine.Arbiter) {
-	println("Exit " + arbiter.GameObjectB().Name())
+	engine.LogPhysics.Debug("Collision exit", "gameObject", arbiter.GameObjectB().Name())
 	if arbiter.GameObjectB() == sp.Floor {
 		sp.Floor = nil
 	}
@@ -63,28 +63,28 @@ func (sp *PlayerController) Start() {
 }
 
 func (sp *PlayerController) TestCoroutines() {
-	autoShoot := func() {
+	autoShoot := func(co *engine.Coroutine) interface{} {
 		for i := 0; i < 3; i++ {
-			engine.CoSleep(3)
+			co.WaitForSeconds(3)
 			sp.Shoot()
 		}
+		return nil
 	}
 
-	as := engine.StartCoroutine(autoShoot)
+	as := sp.StartCoroutine(autoShoot)
 
-	fastShoot := func() {
-		engine.CoSleep(3)
-		engine.CoYieldCoroutine(as)
+	fastShoot := func(co *engine.Coroutine) interface{} {
+		co.WaitForSeconds(3)
+		co.WaitFor(as)
 		for i := 0; i < 10; i++ {
-			engine.CoYieldSkip()
-			engine.CoYieldSkip()
-			engine.CoYieldSkip()
+			co.WaitForFrames(3)
 			sp.Shoot()
 		}
 		sp.TestCoroutines()
+		return nil
 	}
 
-	engine.StartCoroutine(fastShoot)
+	sp.StartCoroutine(fastShoot)
 }
 
 func (sp *PlayerController) Shoot() {
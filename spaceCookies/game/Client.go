@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"github.com/vova616/garageEngine/spaceCookies/server"
 	"github.com/vova616/garageEngine/engine"
-	"log"
 	"net"
 	"time"
 )
@@ -16,7 +15,6 @@ var (
 	LoginErrChan chan error
 )
 
-const ServerIP = "localhost:123"
 const ServerLocalIP = "localhost:123"
 
 type Client struct {
@@ -29,8 +27,9 @@ type Client struct {
 	Encoder *gob.Encoder
 	Decoder *gob.Decoder
 
-	Jobs         chan func()
-	GameJobs     chan func()
+	Jobs chan func()
+	//inGame is done when GameScene runs, see InGame.
+	inGame       *engine.Future
 	Disconnected bool
 
 	lastTransformUpdate        time.Time
@@ -40,14 +39,14 @@ type Client struct {
 func Connect(name string, errChan *chan error) {
 	*errChan = make(chan error)
 	/*
-		addr, err := net.ResolveTCPAddr("tcp", ServerIP)
+		addr, err := net.ResolveTCPAddr("tcp", engine.CurrentConfig().ConnectAddr)
 		if err != nil {
 			*errChan <- err
 			return
 		}
 	*/
 	//con, err := net.DialTCP("tcp", nil, addr)
-	con, err := net.DialTimeout("tcp", ServerIP, time.Second*4)
+	con, err := net.DialTimeout("tcp", engine.CurrentConfig().ConnectAddr, time.Second*4)
 	if err != nil {
 		con, err = net.DialTimeout("tcp", ServerLocalIP, time.Second*4)
 		if err != nil {
@@ -56,21 +55,19 @@ func Connect(name string, errChan *chan error) {
 		}
 	}
 	tcpCon := con.(*net.TCPConn)
-	MyClient = &Client{BaseComponent: engine.NewComponent(), Socket: tcpCon, Name: name, Encoder: gob.NewEncoder(tcpCon), Decoder: gob.NewDecoder(tcpCon), Jobs: make(chan func(), 1000), GameJobs: make(chan func(), 1000)}
+	MyClient = &Client{BaseComponent: engine.NewComponent(), Socket: tcpCon, Name: name, Encoder: gob.NewEncoder(tcpCon), Decoder: gob.NewDecoder(tcpCon), Jobs: make(chan func(), 1000), inGame: engine.NewFuture()}
 	go MyClient.Run()
 	LoginErrChan = *errChan
 }
 
-func (c *Client) Update() {
-	b := true
-	for b {
-		select {
-		case job := <-c.GameJobs:
-			job()
-		default:
-			b = false
+// InGame runs fn on the main thread once GameScene runs, packets that need the game scene go through it.
+// It doesn't block, so packets keep coming while the scene loads.
+func (c *Client) InGame(fn func()) {
+	c.inGame.Then(func(_ interface{}, err error) {
+		if err == nil {
+			fn()
 		}
-	}
+	})
 }
 
 func (c *Client) Send(p server.Packet) {
@@ -123,39 +120,44 @@ func (c *Client) HandlePacket(packet server.Packet) {
 	switch packet.ID() {
 	case server.ID_SpawnPlayer:
 		spawnPlayer := packet.(server.SpawnPlayer)
-		c.GameJobs <- func() {
+		c.InGame(func() {
 			if spawnPlayer.PlayerInfo.PlayerID == c.ID {
 				SpawnMainPlayer(spawnPlayer)
 			} else {
 				SpawnPlayer(spawnPlayer)
 			}
-		}
+		})
 	case server.ID_EnterGame:
 		enterGame := packet.(server.EnterGame)
-		c.ID = enterGame.PlayerID
-		c.Name = enterGame.Name
-		engine.LoadScene(GameSceneGeneral)
+		engine.RunOnMainThread(func() {
+			c.ID = enterGame.PlayerID
+			c.Name = enterGame.Name
+			loader := engine.LoadSceneAsync(GameSceneGeneral, engine.LoadingSceneGeneral)
+			loader.Loaded().Then(func(_ interface{}, err error) {
+				c.inGame.Complete(nil, err)
+			})
+		})
 	case server.ID_LoginError:
 		error := packet.(server.LoginError)
 		LoginErrChan <- fmt.Errorf(error.Error)
 		panic(error)
 	case server.ID_PlayerTransform:
 		trans := packet.(server.PlayerTransform)
-		c.GameJobs <- func() {
+		c.InGame(func() {
 			p, exist := Players[trans.PlayerID]
 			if !exist {
-				println("player does not exists")
+				engine.LogNetwork.Warn("Player does not exist", "id", trans.PlayerID)
 				return
 			}
 			p.Transform().SetPositionf(trans.X, trans.Y)
 			p.Transform().SetRotationf(trans.Rotation)
-		}
+		})
 	}
 }
 
 func (c *Client) OnPanic() {
 	if x := recover(); x != nil && !c.Disconnected {
-		log.Println(c.Name, "Disconnected. Reason:", x)
+		engine.LogNetwork.Info("Disconnected", "name", c.Name, "reason", x)
 		c.Disconnected = true
 		c.Socket.Close()
 		if MyClient == c {
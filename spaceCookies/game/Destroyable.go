@@ -8,11 +8,10 @@ import (
 
 type Destoyable struct {
 	engine.BaseComponent
-	Alive           bool
-	HP              float32
-	FullHP          float32
-	Team            int
-	destoyableFuncs DestoyableFuncs
+	Alive  bool
+	HP     float32
+	FullHP float32
+	Team   int
 
 	createTime    time.Time
 	aliveDuration time.Duration
@@ -23,14 +22,26 @@ func NewDestoyable(hp float32, team int) *Destoyable {
 	return &Destoyable{BaseComponent: engine.NewComponent(), FullHP: hp, Alive: true, HP: hp, Team: team}
 }
 
+// DestoyableFuncs are the messages Destoyable sends to its game object.
 type DestoyableFuncs interface {
 	OnDie(byTimer bool)
 	OnHit(*engine.GameObject, *DamageDealer)
 }
 
 func (ds *Destoyable) Start() {
-	ds.createTime = time.Now()
-	ds.destoyableFuncs, _ = ds.GameObject().ComponentImplements(&ds.destoyableFuncs).(DestoyableFuncs)
+	ds.createTime = engine.GameTime()
+}
+
+// OnSpawn revives a pooled object, it takes the alive duration of the prefab which power ups can change.
+func (ds *Destoyable) OnSpawn() {
+	ds.Alive = true
+	ds.HP = ds.FullHP
+	ds.createTime = engine.GameTime()
+	if pool := ds.GameObject().Pool(); pool != nil {
+		if template, ok := engine.GetComponent[*Destoyable](pool.Prefab().Template()); ok {
+			ds.aliveDuration = template.aliveDuration
+		}
+	}
 }
 
 func (ds *Destoyable) SetDestroyTime(sec float32) {
@@ -40,11 +51,9 @@ func (ds *Destoyable) SetDestroyTime(sec float32) {
 
 func (ds *Destoyable) Update() {
 	if ds.autoDestory && ds.GameObject() != nil {
-		if time.Now().After(ds.createTime.Add(ds.aliveDuration)) {
-			if ds.destoyableFuncs != nil {
-				ds.destoyableFuncs.OnDie(true)
-			} else {
-				ds.GameObject().Destroy()
+		if engine.GameTime().After(ds.createTime.Add(ds.aliveDuration)) {
+			if !ds.GameObject().SendMessage("OnDie", true) {
+				engine.Despawn(ds.GameObject())
 			}
 		}
 	}
@@ -54,35 +63,28 @@ func (ds *Destoyable) OnCollisionEnter(arbiter engine.Arbiter) bool {
 	if !ds.Alive {
 		return true
 	}
-	var dmg *DamageDealer = nil
-	var enemyDestoyable *Destoyable
-
 	enemy := arbiter.GameObjectB()
 
 	if enemy == nil {
 		return true
 	}
 
-	dmg, _ = enemy.ComponentTypeOfi(dmg).(*DamageDealer)
-	enemyDestoyable, _ = enemy.ComponentTypeOfi(enemyDestoyable).(*Destoyable)
+	dmg, _ := engine.GetComponent[*DamageDealer](enemy)
+	enemyDestoyable, ok := engine.GetComponent[*Destoyable](enemy)
 
-	if enemyDestoyable == nil || enemyDestoyable.Team == ds.Team {
+	if !ok || enemyDestoyable.Team == ds.Team {
 		return true
 	}
 
 	if dmg != nil {
 		ds.HP -= dmg.Damage
 	}
-	if ds.destoyableFuncs != nil {
-		ds.destoyableFuncs.OnHit(enemy, dmg)
-	}
+	ds.GameObject().SendMessage("OnHit", enemy, dmg)
 
 	if ds.HP <= 0 {
 		ds.Alive = false
-		if ds.destoyableFuncs != nil {
-			ds.destoyableFuncs.OnDie(false)
-		} else {
-			ds.GameObject().Destroy()
+		if !ds.GameObject().SendMessage("OnDie", false) {
+			engine.Despawn(ds.GameObject())
 		}
 	}
 
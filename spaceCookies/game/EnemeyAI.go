@@ -23,183 +23,207 @@ func NewEnemeyAI(target *engine.GameObject, typ EnemeyType) *EnemeyAI {
 	return &EnemeyAI{BaseComponent: engine.NewComponent(), Target: target, Type: typ}
 }
 
-func (ai *EnemeyAI) Start() {
-	if ai.Target == nil {
-		ai.Target = Player
-	}
-
-	isPlayerClose := func(distance float32) func() engine.Command {
-		return func() engine.Command {
-			if ai.GameObject() == nil || ai.Target.GameObject() == nil {
-				return engine.Close
+// The nodes of the enemy trees in data/SpaceCookies/ai, they find the EnemeyAI of the tree's game object.
+func init() {
+	engine.RegisterNode("TargetAlive", func(p engine.NodeParams, children []engine.Node) (engine.Node, error) {
+		var child engine.Node
+		if len(children) > 0 {
+			child = children[0]
+		}
+		return engine.NewCondition(func(bt *engine.BehaviorTree) bool {
+			ai, ok := engine.GetComponent[*EnemeyAI](bt.GameObject())
+			if !ok || ai.Target.GameObject() == nil {
+				return false
 			}
+			ship, ok := engine.GetComponent[*ShipController](ai.Target)
+			return !ok || ship.IsAlive()
+		}, child), nil
+	})
+	engine.RegisterNode("IsPlayerClose", func(p engine.NodeParams, children []engine.Node) (engine.Node, error) {
+		distance := float32(p.Float("distance", 600))
+		return enemyAction(func(ai *EnemeyAI) engine.Status {
 			myPos := ai.Transform().WorldPosition()
 			targetPos := ai.Target.Transform().WorldPosition()
 			if targetPos.Distance(myPos) < distance {
-				return engine.Continue
+				return engine.StatusSuccess
 			}
-			return engine.Yield
-		}
-	}
-
-	prepareForAttack := func() engine.Command {
-		if ai.GameObject() == nil || ai.Target.GameObject() == nil {
-			return engine.Close
-		}
-
-		ai.GameObject().Physics.Body.SetTorque(10000)
-		return engine.Continue
-	}
-
-	attack := func() engine.Command {
-		if ai.GameObject() == nil || ai.Target.GameObject() == nil {
-			return engine.Close
-		}
-		myPos := ai.Transform().WorldPosition()
-		targetPos := ai.Target.Transform().WorldPosition()
-
-		dir := targetPos.Sub(myPos)
-		dir.Normalize()
+			return engine.StatusFailure
+		}), nil
+	})
+	registerEnemyAction("PrepareForAttack", (*EnemeyAI).prepareForAttack)
+	registerEnemyAction("Attack", (*EnemeyAI).attack)
+	registerEnemyAction("PrepareForNextAttack", (*EnemeyAI).prepareForNextAttack)
+	registerEnemyAction("RandomMove", (*EnemeyAI).randomMove)
+	registerEnemyAction("SendCookies", (*EnemeyAI).sendCookies)
+	registerEnemyAction("StopMoving", (*EnemeyAI).stopMoving)
+	registerEnemyAction("Appear", (*EnemeyAI).appear)
+}
 
-		rnd := rand.Float32() * 0.5
-		if rand.Float32() > 0.5 {
-			rnd = -rnd
+func enemyAction(fn func(ai *EnemeyAI) engine.Status) engine.Node {
+	return engine.NewAction(func(bt *engine.BehaviorTree) engine.Status {
+		ai, ok := engine.GetComponent[*EnemeyAI](bt.GameObject())
+		if !ok {
+			return engine.StatusFailure
 		}
+		return fn(ai)
+	})
+}
 
-		attackSpeed := float32(70000)
-		minAttackSpeed := float32(20000)
-
-		attackSpeed -= minAttackSpeed
+func registerEnemyAction(name string, fn func(ai *EnemeyAI) engine.Status) {
+	engine.RegisterNode(name, func(p engine.NodeParams, children []engine.Node) (engine.Node, error) {
+		return enemyAction(fn), nil
+	})
+}
 
-		ai.GameObject().Physics.Body.AddForce((dir.X+rnd)*((attackSpeed*rand.Float32())+minAttackSpeed), (dir.Y+rnd)*((attackSpeed*rand.Float32())+minAttackSpeed))
-		return engine.Continue
+func (ai *EnemeyAI) Start() {
+	if ai.Target == nil {
+		ai.Target = Player
 	}
 
-	randomMove := func() engine.Command {
-		if ai.GameObject() == nil || ai.Target.GameObject() == nil {
-			return engine.Close
-		}
-		attackSpeed := float32(40000)
-		moveSpeed := float32(20000)
-		myPos := ai.Transform().WorldPosition()
-		targetPos := ai.Target.Transform().WorldPosition()
-		if targetPos.Distance(myPos) < 500 {
-
-			if rand.Float32() > 0.5 {
-
-				dir := targetPos.Sub(myPos)
-				dir.Normalize()
-
-				rnd := rand.Float32() * 0.5
-				if rand.Float32() > 0.5 {
-					rnd = -rnd
-				}
+	co := false
+	if co {
+		ai.StartCoroutine(func(co *engine.Coroutine) interface{} {
+			for {
+				co.WaitForSeconds(5)
+				myPos := ai.Transform().WorldPosition()
+				targetPos := ai.Target.Transform().WorldPosition()
+				if targetPos.Distance(myPos) < 600 {
+					dir := targetPos.Sub(myPos)
+					dir.Normalize()
 
-				ai.GameObject().Physics.Body.AddForce((-dir.X+rnd)*moveSpeed, (-dir.Y+rnd)*moveSpeed)
-			} else {
-				dir := targetPos.Sub(myPos)
-				dir.Normalize()
+					rnd := rand.Float32() * 0.5
+					if rand.Float32() > 0.5 {
+						rnd = -rnd
+					}
 
-				rnd := rand.Float32() * 0.5
-				if rand.Float32() > 0.5 {
-					rnd = -rnd
+					ai.GameObject().Physics.Body.AddForce((dir.X+rnd)*50000, (dir.Y+rnd)*50000)
 				}
-
-				ai.GameObject().Physics.Body.AddForce((dir.X+rnd)*attackSpeed, (dir.Y+rnd)*attackSpeed)
 			}
-		}
-
-		return engine.Continue
+		})
+		return
 	}
 
-	sendCookies := func() engine.Command {
-		if ai.GameObject() == nil || ai.Target.GameObject() == nil {
-			return engine.Close
+	//Clones of the game object already have the steering and the tree of the original.
+	g := ai.GameObject()
+	if _, exists := engine.GetComponent[*engine.Steering](g); !exists {
+		if ai.Type == Enemey_Cookie {
+			g.AddComponent(engine.NewSteering(700, 40000))
+		} else {
+			g.AddComponent(engine.NewSteering(500, 20000))
 		}
-		myPos := ai.Transform().WorldPosition()
-		targetPos := ai.Target.Transform().WorldPosition()
-
-		dir := targetPos.Sub(myPos)
-		dir.Normalize()
-
-		rnd := rand.Float32() * 0.2
-		if rand.Float32() > 0.5 {
-			rnd = -rnd
+		g.AddComponent(engine.NewPursue(ai.Target, 1))
+		g.AddComponent(engine.NewEvade(ai.Target, 0, 1))
+		ai.steer(nil)
+	}
+	if _, exists := engine.GetComponent[*engine.BehaviorTree](g); !exists {
+		bt := engine.NewBehaviorTree(nil)
+		if ai.Type == Enemey_Cookie {
+			bt.Path = "./data/SpaceCookies/ai/cookie.json"
+		} else {
+			bt.Path = "./data/SpaceCookies/ai/queen.yaml"
 		}
+		g.AddComponent(bt)
+	}
+}
 
-		c := cookie.Clone()
-		//c.Tag = CookieTag
-		c.Transform().SetParent2(GameSceneGeneral.Layer2)
-		size := 50 + rand.Float32()*100
-		c.Transform().SetScalef(size, size)
-
-		s := ai.Transform().WorldScale()
-		s = s.Add(c.Transform().WorldScale())
-		s = s.Mul2(0.5)
-		p := myPos.Add(dir.Mul(s))
+// steer enables the steering behavior b and disables the others, nil stops steering.
+func (ai *EnemeyAI) steer(b engine.SteeringBehavior) {
+	for _, c := range ai.GameObject().Components() {
+		if sb, ok := c.(engine.SteeringBehavior); ok {
+			sb.SetEnabled(sb == b)
+		}
+	}
+}
 
-		c.Transform().SetPosition(p)
+func (ai *EnemeyAI) stopMoving() engine.Status {
+	ai.steer(nil)
+	return engine.StatusSuccess
+}
 
-		attackSpeed := float32(70000)
+func (ai *EnemeyAI) prepareForAttack() engine.Status {
+	ai.GameObject().Physics.Body.SetTorque(10000)
+	return engine.StatusSuccess
+}
 
-		c.GameObject().Physics.Body.AddForce((dir.X+rnd)*attackSpeed, (dir.Y+rnd)*attackSpeed)
+// attack chases the target until StopMoving.
+func (ai *EnemeyAI) attack() engine.Status {
+	pursue, ok := engine.GetComponent[*engine.Pursue](ai.GameObject())
+	if !ok {
+		return engine.StatusFailure
+	}
+	pursue.Target = ai.Target
+	ai.steer(pursue)
+	return engine.StatusSuccess
+}
 
-		return engine.Continue
+// randomMove chases or runs from the target when it's close, and stops when it isn't.
+func (ai *EnemeyAI) randomMove() engine.Status {
+	myPos := ai.Transform().WorldPosition()
+	targetPos := ai.Target.Transform().WorldPosition()
+	if targetPos.Distance(myPos) >= 500 {
+		ai.steer(nil)
+		return engine.StatusSuccess
 	}
 
-	appear := func() engine.Command {
-		if ai.GameObject() == nil {
-			return engine.Close
+	g := ai.GameObject()
+	if rand.Float32() > 0.5 {
+		if evade, ok := engine.GetComponent[*engine.Evade](g); ok {
+			evade.Target = ai.Target
+			ai.steer(evade)
+		}
+	} else {
+		if pursue, ok := engine.GetComponent[*engine.Pursue](g); ok {
+			pursue.Target = ai.Target
+			ai.steer(pursue)
 		}
+	}
+	return engine.StatusSuccess
+}
 
-		ai.Transform().SetPositionf(1500, 1500)
+func (ai *EnemeyAI) sendCookies() engine.Status {
+	myPos := ai.Transform().WorldPosition()
+	targetPos := ai.Target.Transform().WorldPosition()
 
-		return engine.Continue
+	dir := targetPos.Sub(myPos)
+	dir.Normalize()
+
+	rnd := rand.Float32() * 0.2
+	if rand.Float32() > 0.5 {
+		rnd = -rnd
 	}
 
-	prepareForNextAttack := func() engine.Command {
-		if ai.GameObject() == nil || ai.Target.GameObject() == nil {
-			return engine.Close
-		}
+	c := cookie.Clone()
+	//c.SetTag(CookieTag)
+	c.Transform().SetParent2(GameSceneGeneral.Layer2)
+	size := 50 + rand.Float32()*100
+	c.Transform().SetScalef(size, size)
 
-		ai.GameObject().Physics.Body.SetTorque(-10)
-		ai.GameObject().Physics.Body.SetAngularVelocity(0)
+	s := ai.Transform().WorldScale()
+	s = s.Add(c.Transform().WorldScale())
+	s = s.Mul2(0.5)
+	p := myPos.Add(dir.Mul(s))
 
-		return engine.Restart
-	}
+	c.Transform().SetPosition(p)
 
-	co := false
-	if co {
-		engine.StartCoroutine(func() {
-			for {
-				engine.CoSleep(5)
-				if !ai.GameObject().IsValid() {
-					return
-				}
-				myPos := ai.Transform().WorldPosition()
-				targetPos := ai.Target.Transform().WorldPosition()
-				if targetPos.Distance(myPos) < 600 {
-					dir := targetPos.Sub(myPos)
-					dir.Normalize()
+	attackSpeed := float32(70000)
 
-					rnd := rand.Float32() * 0.5
-					if rand.Float32() > 0.5 {
-						rnd = -rnd
-					}
+	c.GameObject().Physics.Body.AddForce((dir.X+rnd)*attackSpeed, (dir.Y+rnd)*attackSpeed)
 
-					ai.GameObject().Physics.Body.AddForce((dir.X+rnd)*50000, (dir.Y+rnd)*50000)
-				}
-			}
+	return engine.StatusSuccess
+}
 
-		})
-	} else {
-		if ai.Type == Enemey_Cookie {
-			engine.StartBehavior(engine.SleepRand(5), isPlayerClose(600), prepareForAttack, engine.Sleep(1.5), attack, engine.WaitContinue(prepareForNextAttack, nil, 1.5))
-		} else {
-			engine.StartBehavior(engine.Sleep(120), appear, engine.Sequence(engine.SleepRand(0.5), isPlayerClose(800), randomMove, sendCookies))
-		}
-	}
+func (ai *EnemeyAI) appear() engine.Status {
+	ai.Transform().SetPositionf(1500, 1500)
+
+	return engine.StatusSuccess
+}
+
+// prepareForNextAttack keeps the cookie from spinning until the next attack, it never ends by itself.
+func (ai *EnemeyAI) prepareForNextAttack() engine.Status {
+	ai.GameObject().Physics.Body.SetTorque(-10)
+	ai.GameObject().Physics.Body.SetAngularVelocity(0)
 
+	return engine.StatusRunning
 }
 
 func (ai *EnemeyAI) Update() {
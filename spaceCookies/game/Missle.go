@@ -18,7 +18,7 @@ func NewMissle(speed float32) *Missle {
 }
 
 func (ms *Missle) OnComponentBind(gameObject *engine.GameObject) {
-	gameObject.Tag = MissleTag
+	gameObject.SetTag(MissleTag)
 }
 
 func (ms *Missle) OnHit(enemey *engine.GameObject, damager *DamageDealer) {
@@ -27,7 +27,7 @@ func (ms *Missle) OnHit(enemey *engine.GameObject, damager *DamageDealer) {
 
 func (ms *Missle) OnDie(byTimer bool) {
 	if ms.Explosion == nil {
-		ms.GameObject().Destroy()
+		engine.Despawn(ms.GameObject())
 		return
 	}
 	if ms.GameObject() == nil {
@@ -50,5 +50,5 @@ func (ms *Missle) OnDie(byTimer bool) {
 			n.Physics.Shape.IsSensor = true
 		}
 	}
-	ms.GameObject().Destroy()
+	engine.Despawn(ms.GameObject())
 }
@@ -24,7 +24,7 @@ func (m *MouseDebugger) Update() {
 			mousePosition := m.Transform().WorldPosition()
 
 			c := cookie.Clone()
-			//c.Tag = CookieTag
+			//c.SetTag(CookieTag)
 			c.Transform().SetParent2(GameSceneGeneral.Layer2)
 			size := 25 + rand.Float32()*100
 			c.Transform().SetPosition(mousePosition)
@@ -48,8 +48,8 @@ func (m *MouseDebugger) Update() {
 
 	}
 
-	if input.KeyPress('R') {
-		MyClient.SendRespawn()
+	if input.KeyPress('R') && PlayerShip != nil {
+		PlayerShip.Respawn()
 	}
 	if queenDead {
 		if input.KeyPress(input.KeyF1) {
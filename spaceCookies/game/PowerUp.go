@@ -32,9 +32,7 @@ func NewPowerUp(typ Power) *PowerUp {
 func CreatePowerUp(position engine.Vector) {
 	chance := rand.Int() % 100
 	if chance <= PowerUpChance {
-		c := PowerUpGO.Clone()
-		c.Transform().SetParent2(GameSceneGeneral.Layer2)
-		c.Transform().SetPosition(position)
+		c := engine.Instantiate(PowerUpPrefab, position, GameSceneGeneral.Layer2)
 
 		index := (rand.Int() % 6)
 
@@ -48,9 +46,7 @@ func CreatePowerUp(position engine.Vector) {
 
 		c.AddComponent(NewPowerUp(Power(index - 5)))
 	} else if chance <= PowerUpRepairChance {
-		c := PowerUpGO.Clone()
-		c.Transform().SetParent2(GameSceneGeneral.Layer2)
-		c.Transform().SetPosition(position)
+		c := engine.Instantiate(PowerUpPrefab, position, GameSceneGeneral.Layer2)
 
 		index := int(HP) - 1
 
@@ -82,8 +78,7 @@ func PowerUpShip(p Power) {
 			PlayerShip.Speed += 30000
 		case Damage:
 			/*
-				var dmg *DamageDealer
-				dmg = PlayerShip.Missle.GameObject().ComponentTypeOfi(dmg).(*DamageDealer)
+				dmg, _ := engine.GetComponent[*DamageDealer](PlayerShip.Missle.GameObject())
 				dmg.Damage += 50
 			*/
 			PlayerShip.MissleLevel++
@@ -91,9 +86,9 @@ func PowerUpShip(p Power) {
 				PlayerShip.MissleLevel = PlayerShip.MaxMissleLevel
 			}
 		case Range:
-			var dst *Destoyable
-			dst = PlayerShip.Missle.GameObject().ComponentTypeOfi(dst).(*Destoyable)
-			dst.aliveDuration += time.Millisecond * 100
+			if dst, ok := engine.GetComponent[*Destoyable](PlayerShip.Missle.GameObject()); ok {
+				dst.aliveDuration += time.Millisecond * 100
+			}
 		case HP:
 			PlayerShip.Destoyable.HP = PlayerShip.Destoyable.FullHP
 			PlayerShip.OnHit(nil, nil)
@@ -0,0 +1,16 @@
+package game
+
+import (
+	"github.com/vova616/garageEngine/engine"
+)
+
+func init() {
+	engine.RegisterComponent("DamageDealer", func() engine.Component { return NewDamageDealer(0) })
+	engine.RegisterComponent("Destoyable", func() engine.Component { return NewDestoyable(1, 0) })
+	engine.RegisterComponent("Missle", func() engine.Component { return NewMissle(0) }).Require("Physics", "DamageDealer")
+	engine.RegisterComponent("PowerUp", func() engine.Component { return NewPowerUp(Speed) }).Require("Physics").Range("Type", 1, 6)
+	engine.RegisterComponent("ResizeScript", func() engine.Component { return NewResizeScript(1, 1, 1, 1, 1, 1) })
+
+	//The client sends the ship transform after everything else moved it.
+	engine.SetExecutionOrder(&Client{}, 1000)
+}
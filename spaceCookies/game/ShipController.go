@@ -34,6 +34,9 @@ type ShipController struct {
 	JetFireParent   *engine.GameObject `json:"-"`
 	JetFirePool     []*ResizeScript    `json:"-"`
 	JetFirePosition []engine.Vector    `json:"-"`
+
+	misslePool *engine.Pool
+	state      *engine.StateMachine
 }
 
 func NewShipController() *ShipController {
@@ -44,19 +47,60 @@ func NewShipController() *ShipController {
 	misslePositions := []engine.Vector{{-28, 10, 0}, {28, 10, 0}, {0, 20, 0}, {-28, 40, 0}, {28, 40, 0}}
 
 	return &ShipController{engine.NewComponent(), 500000, 250, nil, misslePositions, misslesDirection, 0, len(misslesDirection) - 1,
-		time.Now(), nil, nil, true, nil, nil, nil, []engine.Vector{{-0.1, -0.51, 0}, {0.1, -0.51, 0}}}
+		engine.GameTime(), nil, nil, true, nil, nil, nil, []engine.Vector{{-0.1, -0.51, 0}, {0.1, -0.51, 0}}, nil, nil}
 }
 
 func (sp *ShipController) OnComponentBind(binded *engine.GameObject) {
 
 	sp.GameObject().AddComponent(engine.NewPhysics2(false, chipmunk.NewCircle(vect.Vect{0, 0}, 15)))
+	sp.state = sp.GameObject().AddComponent(sp.newStateMachine()).(*engine.StateMachine)
+}
+
+// newStateMachine creates the life of the ship, it's controlled while it's Alive, explodes and hides when it dies
+// and asks the server for a new spawn point on Respawn.
+func (sp *ShipController) newStateMachine() *engine.StateMachine {
+	sm := engine.NewStateMachine()
+	alive := sm.AddState("Alive")
+	alive.OnEnter = func(*engine.StateMachine) { sp.revive() }
+	alive.OnUpdate = func(*engine.StateMachine) { sp.control() }
+	sm.AddState("Dead").OnEnter = func(*engine.StateMachine) { sp.explode() }
+	sm.AddState("Respawn").OnEnter = func(sm *engine.StateMachine) {
+		if MyClient == nil {
+			sm.Fire("spawned")
+			return
+		}
+		if err := MyClient.SendRespawn(); err != nil {
+			engine.LogNetwork.Error("Respawn failed", "err", err)
+			sm.SetState("Dead")
+		}
+	}
+
+	sm.AddTransition("Alive", "Dead").On("die")
+	sm.AddTransition("Dead", "Respawn").On("respawn")
+	sm.AddTransition("Respawn", "Alive").On("spawned")
+	return sm
+}
+
+// IsAlive returns false while the ship is dead or waits to respawn.
+func (sp *ShipController) IsAlive() bool {
+	return sp.state.Current() == nil || sp.state.IsIn("Alive")
+}
+
+// Respawn asks for a new ship if the ship is dead.
+func (sp *ShipController) Respawn() {
+	sp.state.Fire("respawn")
+}
+
+// Spawned brings the ship back after the server sent its new spawn point.
+func (sp *ShipController) Spawned() {
+	sp.state.Fire("spawned")
 }
 
 func (sp *ShipController) Start() {
 	ph := sp.GameObject().Physics
 	ph.Body.SetMass(50)
 	ph.Shape.Group = 1
-	sp.Destoyable = sp.GameObject().ComponentTypeOfi(sp.Destoyable).(*Destoyable)
+	sp.Destoyable, _ = engine.GetComponent[*Destoyable](sp.GameObject())
 	sp.OnHit(nil, nil)
 
 	sp.JetFireParent = engine.NewGameObject("JetFireParent")
@@ -104,6 +148,23 @@ func (sp *ShipController) OnHit(enemey *engine.GameObject, damager *DamageDealer
 }
 
 func (sp *ShipController) OnDie(byTimer bool) {
+	sp.state.Fire("die")
+}
+
+// revive shows the ship again with full HP.
+func (sp *ShipController) revive() {
+	if sp.Destoyable == nil {
+		return
+	}
+	sp.Destoyable.Alive = true
+	sp.Destoyable.HP = sp.Destoyable.FullHP
+	sp.OnHit(nil, nil)
+	sp.GameObject().Sprite.SetEnabled(true)
+	sp.GameObject().Physics.SetEnabled(true)
+}
+
+// explode hides the ship instead of destroying it, the client and the state machine live on its game object.
+func (sp *ShipController) explode() {
 	for i := 0; i < 20; i++ {
 		n := Explosion.Clone()
 		n.Transform().SetParent2(GameSceneGeneral.Layer1)
@@ -120,7 +181,15 @@ func (sp *ShipController) OnDie(byTimer bool) {
 		n.Physics.Shape.Group = 1
 		n.Physics.Shape.IsSensor = true
 	}
-	sp.GameObject().Destroy()
+	sp.GameObject().Sprite.SetEnabled(false)
+	sp.GameObject().Physics.SetEnabled(false)
+	sp.JetFireParent.SetActiveRecursive(false)
+}
+
+func (sp *ShipController) OnDestroy() {
+	if sp.misslePool != nil {
+		sp.misslePool.Destroy()
+	}
 }
 
 func (sp *ShipController) Shoot() {
@@ -142,12 +211,17 @@ func (sp *ShipController) Shoot() {
 			m.Translate(p.X, p.Y, p.Z)
 			p = m.Translation()
 
-			nfire := sp.Missle.GameObject().Clone()
-			nfire.Transform().SetParent2(GameSceneGeneral.Layer3)
-			nfire.Transform().SetWorldPosition(p)
+			if sp.misslePool == nil || sp.misslePool.Prefab().Template() != sp.Missle.GameObject() {
+				//The missiles of the old template are not spawned again.
+				if sp.misslePool != nil {
+					sp.misslePool.Destroy()
+				}
+				sp.misslePool = engine.NewPool(engine.NewPrefab(sp.Missle.GameObject()), 32)
+			}
+			nfire := sp.misslePool.Spawn(p, GameSceneGeneral.Layer3)
 			nfire.Physics.Body.IgnoreGravity = true
 			nfire.Physics.Body.SetMass(0.1)
-			nfire.Tag = MissleTag
+			nfire.SetTag(MissleTag)
 
 			v := sp.GameObject().Physics.Body.Velocity()
 			angle := float32(math.Atan2(float64(s.X), float64(s.Y))) * engine.DegreeConst
@@ -162,7 +236,8 @@ func (sp *ShipController) Shoot() {
 	}
 }
 
-func (sp *ShipController) Update() {
+// control moves the ship and shoots, it's called every Update while the ship is alive.
+func (sp *ShipController) control() {
 	delta := float32(engine.DeltaTime())
 	r2 := sp.Transform().DirectionTransform(engine.Up)
 	r3 := sp.Transform().DirectionTransform(engine.Left)
@@ -249,9 +324,9 @@ func (sp *ShipController) Update() {
 	}
 
 	if input.MouseDown(input.MouseLeft) {
-		if time.Now().After(sp.lastShoot) {
+		if engine.GameTime().After(sp.lastShoot) {
 			sp.Shoot()
-			sp.lastShoot = time.Now().Add(time.Millisecond * 200)
+			sp.lastShoot = engine.GameTime().Add(time.Millisecond * 200)
 		}
 	}
 
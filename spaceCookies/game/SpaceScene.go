@@ -1,7 +1,6 @@
 package game
 
 import (
-	"fmt"
 	"github.com/vova616/garageEngine/engine"
 	"github.com/vova616/garageEngine/engine/components"
 	_ "image/jpeg"
@@ -41,7 +40,7 @@ var (
 	PlayerShip *ShipController
 
 	Explosion *engine.GameObject
-	PowerUpGO *engine.GameObject
+	PowerUpPrefab *engine.Prefab
 
 	Wall *engine.GameObject
 
@@ -74,13 +73,19 @@ const Jet_A = 125
 
 func CheckError(err error) bool {
 	if err != nil {
-		fmt.Println(err)
+		engine.LogAssets.Error("Loading failed", "err", err)
 		return true
 	}
 	return false
 }
 
 func LoadTextures() {
+	PrepareTextures(nil)
+}
+
+// PrepareTextures loads all the game textures, with a loader it can run in the background and
+// only the texture uploads are done on the main thread.
+func PrepareTextures(loader *engine.SceneLoader) {
 	atlas = engine.NewManagedAtlas(2048, 1024)
 	atlasSpace = engine.NewManagedAtlas(1024, 1024)
 	atlasPowerUp = engine.NewManagedAtlas(256, 256)
@@ -97,52 +102,85 @@ func LoadTextures() {
 	CheckError(atlas.LoadImageID("./data/spaceCookies/Queen.png", Queen_A))
 	CheckError(atlas.LoadImageID("./data/spaceCookies/Jet.png", Jet_A))
 
-	atlas.BuildAtlas()
-	atlas.BuildMipmaps()
-	atlas.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
-	atlas.Texture.SetReadOnly()
-
-	boxt, e = engine.LoadTexture("./data/spaceCookies/wall.png")
-
-	boxt.BuildMipmaps()
-	boxt.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
+	CheckError(atlas.PrepareAtlas())
+	loader.Upload(func() {
+		atlas.Upload()
+		atlas.BuildMipmaps()
+		atlas.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
+		atlas.Texture.SetReadOnly()
+	})
+	loader.SetProgress(0.2)
 
-	backgroung, e = engine.LoadTexture("./data/spaceCookies/background.png")
+	boxImg, e := engine.LoadImage("./data/spaceCookies/wall.png")
 	CheckError(e)
-	cir, e = engine.LoadTexture("./data/spaceCookies/Cookie.png")
+	backgroungImg, e := engine.LoadImage("./data/spaceCookies/background.png")
+	CheckError(e)
+	cirImg, e := engine.LoadImage("./data/spaceCookies/Cookie.png")
 	CheckError(e)
 
-	cir.BuildMipmaps()
-	cir.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
-
-	backgroung.BuildMipmaps()
-	backgroung.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
+	loader.Upload(func() {
+		boxt, e = engine.LoadTextureFromImage(boxImg)
+		CheckError(e)
+		boxt.BuildMipmaps()
+		boxt.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
+
+		backgroung, e = engine.LoadTextureFromImage(backgroungImg)
+		CheckError(e)
+		backgroung.BuildMipmaps()
+		backgroung.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
+
+		cir, e = engine.LoadTextureFromImage(cirImg)
+		CheckError(e)
+		cir.BuildMipmaps()
+		cir.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
+	})
+	loader.SetProgress(0.4)
 
 	CheckError(atlasSpace.LoadGroup("./data/spaceCookies/Space/"))
-	atlasSpace.BuildAtlas()
-	atlasSpace.BuildMipmaps()
-	atlasSpace.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
-	atlasSpace.Texture.SetReadOnly()
+	CheckError(atlasSpace.PrepareAtlas())
+	loader.Upload(func() {
+		atlasSpace.Upload()
+		atlasSpace.BuildMipmaps()
+		atlasSpace.SetFiltering(engine.MipMapLinearNearest, engine.Nearest)
+		atlasSpace.Texture.SetReadOnly()
+	})
 
 	e, PowerUps_ID = atlasPowerUp.LoadGroupSheet("./data/spaceCookies/powerups.png", 61, 61, 3*4)
 	CheckError(e)
-	atlasPowerUp.BuildAtlas()
-	atlasPowerUp.SetFiltering(engine.Linear, engine.Linear)
+	CheckError(atlasPowerUp.PrepareAtlas())
+	loader.Upload(func() {
+		atlasPowerUp.Upload()
+		atlasPowerUp.SetFiltering(engine.Linear, engine.Linear)
+	})
+	loader.SetProgress(0.6)
 
-	ArialFont, e = engine.NewFont("./data/Fonts/arial.ttf", 48)
+	ArialFont, e = engine.PrepareFont("./data/Fonts/arial.ttf", 48)
 	if e != nil {
 		panic(e)
 	}
-	ArialFont.Texture.SetReadOnly()
+	loader.SetProgress(0.8)
 
-	ArialFont2, e = engine.NewFont("./data/Fonts/arial.ttf", 24)
+	ArialFont2, e = engine.PrepareFont("./data/Fonts/arial.ttf", 24)
 	if e != nil {
 		panic(e)
 	}
-	ArialFont2.Texture.SetReadOnly()
+
+	loader.Upload(func() {
+		CheckError(ArialFont.Upload())
+		ArialFont.Texture.SetReadOnly()
+		CheckError(ArialFont2.Upload())
+		ArialFont2.Texture.SetReadOnly()
+	})
 }
 
 func SpawnMainPlayer(spawnPlayer server.SpawnPlayer) {
+	//A respawn moves the ship that died.
+	if PlayerShip != nil && PlayerShip.GameObject() == Player {
+		Player.Transform().SetWorldPositionf(spawnPlayer.PlayerTransform.X, spawnPlayer.PlayerTransform.Y)
+		Player.Transform().SetWorldRotationf(spawnPlayer.PlayerTransform.Rotation)
+		PlayerShip.Spawned()
+		return
+	}
 	Health := engine.NewGameObject("HP")
 	Health.Transform().SetParent2(GameSceneGeneral.Camera.GameObject())
 	Health.Transform().SetPositionf(150, 50)
@@ -197,9 +235,13 @@ func SpawnPlayer(spawnPlayer server.SpawnPlayer) {
 	}
 }
 
+func (s *GameScene) Prepare(loader *engine.SceneLoader) error {
+	PrepareTextures(loader)
+	return nil
+}
+
 func (s *GameScene) Load() {
 	Players = make(map[server.ID]*engine.GameObject)
-	LoadTextures()
 	engine.SetTitle("Space Cookies")
 	queenDead = false
 
@@ -330,14 +372,14 @@ func (s *GameScene) Load() {
 	cookie.Transform().SetScalef(50, 50)
 	cookie.Transform().SetPositionf(400, 400)
 	cookie.AddComponent(engine.NewPhysics2(false, chipmunk.NewCircle(vect.Vect{0, 0}, 25)))
-	cookie.Tag = CookieTag
+	cookie.SetTag(CookieTag)
 
 	defender = engine.NewGameObject("Box")
 	ds = NewDestoyable(30, 3)
 	ds.SetDestroyTime(5)
 	defender.AddComponent(ds)
 	defender.AddComponent(engine.NewSprite(boxt))
-	defender.Tag = CookieTag
+	defender.SetTag(CookieTag)
 	defender.Transform().SetScalef(50, 50)
 
 	phx := defender.AddComponent(engine.NewPhysics(false, 50, 50)).(*engine.Physics)
@@ -356,7 +398,7 @@ func (s *GameScene) Load() {
 	QueenCookie.Transform().SetScalef(300, 300)
 	QueenCookie.Transform().SetPositionf(999999, 999999)
 	QueenCookie.AddComponent(engine.NewPhysics2(false, chipmunk.NewCircle(vect.Vect{0, 0}, 25)))
-	QueenCookie.Tag = CookieTag
+	QueenCookie.SetTag(CookieTag)
 
 	staticCookie := engine.NewGameObject("Cookie")
 	staticCookie.AddComponent(engine.NewSprite(cir))
@@ -368,7 +410,7 @@ func (s *GameScene) Load() {
 	staticCookie.Physics.Shape.SetElasticity(0)
 	staticCookie.Physics.Body.SetMass(999999999999)
 	staticCookie.Physics.Body.SetMoment(staticCookie.Physics.Shape.Moment(999999999999))
-	staticCookie.Tag = CookieTag
+	staticCookie.SetTag(CookieTag)
 
 	uvs, ind = engine.AnimatedGroupUVs(atlasSpace, "s")
 	Background := engine.NewGameObject("Background")
@@ -380,18 +422,19 @@ func (s *GameScene) Load() {
 	Background.Transform().SetPositionf(400, 400)
 
 	uvs, ind = engine.AnimatedGroupUVs(atlasPowerUp, PowerUps_ID)
-	PowerUpGO = engine.NewGameObject("Background")
-	//PowerUpGO.Transform().SetParent2(Layer2)
-	PowerUpGO.AddComponent(engine.NewSprite3(atlasPowerUp.Texture, uvs))
-	PowerUpGO.AddComponent(engine.NewPhysics(false, 61, 61))
-	PowerUpGO.Physics.Shape.IsSensor = true
-	PowerUpGO.Sprite.BindAnimations(ind)
-	PowerUpGO.Sprite.SetAnimation(PowerUps_ID)
-	PowerUpGO.Sprite.AnimationSpeed = 0
+	powerUp := engine.NewGameObject("Background")
+	//powerUp.Transform().SetParent2(Layer2)
+	powerUp.AddComponent(engine.NewSprite3(atlasPowerUp.Texture, uvs))
+	powerUp.AddComponent(engine.NewPhysics(false, 61, 61))
+	powerUp.Physics.Shape.IsSensor = true
+	powerUp.Sprite.BindAnimations(ind)
+	powerUp.Sprite.SetAnimation(PowerUps_ID)
+	powerUp.Sprite.AnimationSpeed = 0
 	index := (rand.Int() % 6) + 6
-	PowerUpGO.Sprite.SetAnimationIndex(int(index))
-	PowerUpGO.Transform().SetScalef(61, 61)
-	PowerUpGO.Transform().SetPositionf(0, 0)
+	powerUp.Sprite.SetAnimationIndex(int(index))
+	powerUp.Transform().SetScalef(61, 61)
+	powerUp.Transform().SetPositionf(0, 0)
+	PowerUpPrefab = engine.NewPrefab(powerUp)
 
 	background := engine.NewGameObject("Background")
 	background.AddComponent(engine.NewSprite(backgroung))
@@ -419,7 +462,7 @@ func (s *GameScene) Load() {
 
 	for i := 0; i < 600; i++ {
 		c := cookie.Clone()
-		//c.Tag = CookieTag
+		//c.SetTag(CookieTag)
 		c.Transform().SetParent2(Layer2)
 		size := 40 + rand.Float32()*100
 		p := engine.Vector{(rand.Float32() * 4000), (rand.Float32() * 4000), 1}
@@ -474,7 +517,7 @@ func (s *GameScene) Load() {
 	s.AddGameObject(background)
 	//s.AddGameObject(shadowShader)
 
-	fmt.Println("GameScene loaded")
+	engine.LogScene.Info("Scene loaded", "scene", s.Name())
 }
 
 func (s *GameScene) New() engine.Scene {
@@ -598,7 +641,7 @@ func (s *GameScene) OldLoad() {
 	if e != nil {
 		f, e = os.Create("./data/spaceCookies/game.dat")
 		if e != nil {
-			fmt.Println(e)
+			engine.LogAssets.Error("Settings file creation failed", "err", e)
 		}
 		defer f.Close()
 		encoder := json.NewEncoder(f)
@@ -609,7 +652,7 @@ func (s *GameScene) OldLoad() {
 	decoder := json.NewDecoder(f)
 	e = decoder.Decode(&settings)
 	if e != nil {
-		fmt.Println(e)
+		engine.LogAssets.Error("Settings decoding failed", "err", e)
 	}
 	ship.AddComponent(NewDestoyable(shipHP, 1))
 
@@ -645,14 +688,14 @@ func (s *GameScene) OldLoad() {
 	cookie.Transform().SetScalef(50, 50)
 	cookie.Transform().SetPositionf(400, 400)
 	cookie.AddComponent(engine.NewPhysics2(false, chipmunk.NewCircle(vect.Vect{0, 0}, 25)))
-	cookie.Tag = CookieTag
+	cookie.SetTag(CookieTag)
 
 	defender = engine.NewGameObject("Box")
 	ds = NewDestoyable(30, 3)
 	ds.SetDestroyTime(5)
 	defender.AddComponent(ds)
 	defender.AddComponent(engine.NewSprite(boxt))
-	defender.Tag = CookieTag
+	defender.SetTag(CookieTag)
 	defender.Transform().SetScalef(50, 50)
 
 	phx := defender.AddComponent(engine.NewPhysics(false, 50, 50)).(*engine.Physics)
@@ -671,7 +714,7 @@ func (s *GameScene) OldLoad() {
 	QueenCookie.Transform().SetScalef(300, 300)
 	QueenCookie.Transform().SetPositionf(999999, 999999)
 	QueenCookie.AddComponent(engine.NewPhysics2(false, chipmunk.NewCircle(vect.Vect{0, 0}, 25)))
-	QueenCookie.Tag = CookieTag
+	QueenCookie.SetTag(CookieTag)
 
 	staticCookie := engine.NewGameObject("Cookie")
 	staticCookie.AddComponent(engine.NewSprite(cir))
@@ -683,7 +726,7 @@ func (s *GameScene) OldLoad() {
 	staticCookie.Physics.Shape.SetElasticity(0)
 	staticCookie.Physics.Body.SetMass(999999999999)
 	staticCookie.Physics.Body.SetMoment(staticCookie.Physics.Shape.Moment(999999999999))
-	staticCookie.Tag = CookieTag
+	staticCookie.SetTag(CookieTag)
 
 	uvs, ind = engine.AnimatedGroupUVs(atlasSpace, "s")
 	Background := engine.NewGameObject("Background")
@@ -695,18 +738,19 @@ func (s *GameScene) OldLoad() {
 	Background.Transform().SetPositionf(400, 400)
 
 	uvs, ind = engine.AnimatedGroupUVs(atlasPowerUp, PowerUps_ID)
-	PowerUpGO = engine.NewGameObject("Background")
-	//PowerUpGO.Transform().SetParent2(Layer2)
-	PowerUpGO.AddComponent(engine.NewSprite3(atlasPowerUp.Texture, uvs))
-	PowerUpGO.AddComponent(engine.NewPhysics(false, 61, 61))
-	PowerUpGO.Physics.Shape.IsSensor = true
-	PowerUpGO.Sprite.BindAnimations(ind)
-	PowerUpGO.Sprite.SetAnimation(PowerUps_ID)
-	PowerUpGO.Sprite.AnimationSpeed = 0
+	powerUp := engine.NewGameObject("Background")
+	//powerUp.Transform().SetParent2(Layer2)
+	powerUp.AddComponent(engine.NewSprite3(atlasPowerUp.Texture, uvs))
+	powerUp.AddComponent(engine.NewPhysics(false, 61, 61))
+	powerUp.Physics.Shape.IsSensor = true
+	powerUp.Sprite.BindAnimations(ind)
+	powerUp.Sprite.SetAnimation(PowerUps_ID)
+	powerUp.Sprite.AnimationSpeed = 0
 	index := (rand.Int() % 6) + 6
-	PowerUpGO.Sprite.SetAnimationIndex(int(index))
-	PowerUpGO.Transform().SetScalef(61, 61)
-	PowerUpGO.Transform().SetPositionf(0, 0)
+	powerUp.Sprite.SetAnimationIndex(int(index))
+	powerUp.Transform().SetScalef(61, 61)
+	powerUp.Transform().SetPositionf(0, 0)
+	PowerUpPrefab = engine.NewPrefab(powerUp)
 
 	background := engine.NewGameObject("Background")
 	background.AddComponent(engine.NewSprite(backgroung))
@@ -734,7 +778,7 @@ func (s *GameScene) OldLoad() {
 
 	for i := 0; i < 600; i++ {
 		c := cookie.Clone()
-		//c.Tag = CookieTag
+		//c.SetTag(CookieTag)
 		c.Transform().SetParent2(Layer2)
 		size := 40 + rand.Float32()*100
 		p := engine.Vector{(rand.Float32() * 4000), (rand.Float32() * 4000), 1}
@@ -789,5 +833,5 @@ func (s *GameScene) OldLoad() {
 	s.AddGameObject(background)
 	//s.AddGameObject(shadowShader)
 
-	fmt.Println("GameScene loaded")
+	engine.LogScene.Info("Scene loaded", "scene", s.Name())
 }
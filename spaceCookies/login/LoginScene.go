@@ -1,7 +1,6 @@
 package login
 
 import (
-	"fmt"
 	"github.com/vova616/garageEngine/engine"
 	"github.com/vova616/garageEngine/engine/components"
 	//"github.com/vova616/garageEngine/engine/components/tween"
@@ -38,7 +37,7 @@ var (
 
 func CheckError(err error) bool {
 	if err != nil {
-		fmt.Println(err)
+		engine.LogAssets.Error("Loading failed", "err", err)
 		return true
 	}
 	return false
@@ -46,18 +45,18 @@ func CheckError(err error) bool {
 
 func LoadTextures() {
 	var e error
-	ArialFont, e = engine.NewFont("./data/Fonts/arial.ttf", 24)
+	ArialFont, e = engine.Assets.Font("./data/Fonts/arial.ttf", 24)
 	CheckError(e)
 	ArialFont.Texture.SetReadOnly()
 
-	ArialFont2, e = engine.NewFont("./data/Fonts/arial.ttf", 24)
+	ArialFont2, e = engine.Assets.Font("./data/Fonts/arial.ttf", 24)
 	CheckError(e)
 	ArialFont2.Texture.SetReadOnly()
 
-	backgroundTexture, e = engine.LoadTexture("./data/spaceCookies/background.png")
+	backgroundTexture, e = engine.Assets.Texture("./data/spaceCookies/background.png")
 	CheckError(e)
 
-	button, e = engine.LoadTexture("./data/spaceCookies/button.png")
+	button, e = engine.Assets.Texture("./data/spaceCookies/button.png")
 	CheckError(e)
 }
 
@@ -267,13 +266,9 @@ func (s *LoginScene) Load() {
 	txt2.Color = engine.Color{1, 1, 1, 1}
 	//	
 
-	engine.StartCoroutine(func() {
+	errLabel.StartCoroutine(func(co *engine.Coroutine) interface{} {
 		for {
-
-			if errChan == nil {
-				engine.CoYieldSkip()
-				continue
-			}
+			co.WaitUntil(func() bool { return errChan != nil })
 			select {
 			case loginErr := <-errChan:
 				if loginErr != nil {
@@ -283,7 +278,7 @@ func (s *LoginScene) Load() {
 			default:
 
 			}
-			engine.CoYieldSkip()
+			co.WaitForFrames(1)
 		}
 	})
 
@@ -294,7 +289,7 @@ func (s *LoginScene) Load() {
 	s.AddGameObject(cam)
 	s.AddGameObject(background)
 
-	fmt.Println("LoginScene loaded")
+	engine.LogScene.Info("Scene loaded", "scene", s.Name())
 }
 
 func (s *LoginScene) New() engine.Scene {
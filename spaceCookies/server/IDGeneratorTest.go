@@ -2,11 +2,10 @@ package server
 
 import "runtime"
 import "time"
-import "log"
 
 func TestGenerator() {
 	runtime.GOMAXPROCS(8)
-	defer log.Println("Done!")
+	defer logNetwork.Info("Done!")
 	size := 100000
 	ch := make(chan ID, size+1)
 
@@ -25,7 +24,7 @@ func TestGenerator() {
 			continue
 		}
 		for id := range ch {
-			log.Println(id, len(ch))
+			logNetwork.Debug("ID", "id", id, "left", len(ch))
 			if len(ch) == 0 {
 				return
 			}
@@ -1,18 +1,17 @@
 package server
 
 import "strings"
-import "log"
 
 func OnWelcomePacket(c *Client, p Packet) {
 	welcomePacket := p.(Welcome)
 
 	if strings.ToLower(welcomePacket.Name) == "admin" {
 		c.Send(NewLoginError("YOU NO ADMIN!#."))
-		log.Println("YOU NO ADMIN!#", c.Socket.RemoteAddr(), c.ID)
+		logNetwork.Warn("Admin login refused", "addr", c.Socket.RemoteAddr(), "id", c.ID)
 		return
 	} else if len(welcomePacket.Name) == 0 {
 		c.Send(NewLoginError("Empty name, try again."))
-		log.Println("Empty name", c.Socket.RemoteAddr(), c.ID)
+		logNetwork.Warn("Empty name", "addr", c.Socket.RemoteAddr(), "id", c.ID)
 		return
 	}
 
@@ -26,10 +25,10 @@ func OnWelcomePacket(c *Client, p Packet) {
 
 	if nameExists {
 		c.Send(NewLoginError("YOU SHALL NOT PASS (this name is already taken)."))
-		log.Println("YOU SHALL NOT PASS", c.Socket.RemoteAddr(), c.ID)
+		logNetwork.Warn("Name is taken", "addr", c.Socket.RemoteAddr(), "id", c.ID)
 	} else {
 		c.Name = welcomePacket.Name
-		log.Println("Connection in!", c.Socket.RemoteAddr(), c.ID, c.Name)
+		logNetwork.Info("Client connected", "addr", c.Socket.RemoteAddr(), "id", c.ID, "name", c.Name)
 		PlayerEnterGame(c)
 	}
 }
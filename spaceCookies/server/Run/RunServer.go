@@ -1,7 +1,13 @@
 package main
 
-import "github.com/vova616/garageEngine/spaceCookies/server"
+import (
+	"flag"
+	"github.com/vova616/garageEngine/spaceCookies/server"
+)
+
+var addr = flag.String("server-addr", "0.0.0.0:123", "address the server listens on")
 
 func main() {
-	server.StartServer()
+	flag.Parse()
+	server.StartServer(*addr)
 }
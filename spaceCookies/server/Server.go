@@ -2,13 +2,16 @@ package server
 
 import (
 	"encoding/gob"
-	"log"
+	"github.com/vova616/garageEngine/engine/logging"
 	"net"
 	"sync/atomic"
 )
 
 var MainServer *Server
 
+// logNetwork is engine.LogNetwork, the server uses the logging package so it doesn't import the engine (and OpenGL).
+var logNetwork = logging.NewLogger("network")
+
 type Job func()
 type Server struct {
 	Socket  *net.TCPListener
@@ -75,7 +78,7 @@ func (c *Client) Send(p Packet) {
 func (c *Client) OnPanic() {
 	if x := recover(); x != nil {
 		if atomic.CompareAndSwapInt32(&c.Disconnected, 0, 1) {
-			log.Println(c.Name, "Disconnected. Reason:", x)
+			logNetwork.Info("Client disconnected", "name", c.Name, "reason", x)
 			MainServer.Jobs <- func() {
 				delete(MainServer.Clients, c.ID)
 				MainServer.IDGen.PutID(c.ID)
@@ -84,18 +87,19 @@ func (c *Client) OnPanic() {
 	}
 }
 
-func StartServer() {
-	addr, err := net.ResolveTCPAddr("tcp", "0.0.0.0:123")
+// StartServer listens on address ("0.0.0.0:123") and serves the clients, it returns if listening fails.
+func StartServer(address string) {
+	addr, err := net.ResolveTCPAddr("tcp", address)
 	if err != nil {
-		log.Println(err)
+		logNetwork.Error("Server address is invalid", "err", err)
 		return
 	}
 	ln, err := net.ListenTCP("tcp", addr)
 	if err != nil {
-		log.Println(err)
+		logNetwork.Error("Server listen failed", "err", err)
 		return
 	}
-	log.Println("Server started!")
+	logNetwork.Info("Server started", "addr", addr)
 	//MainServer.IDGen can be not safe because the only place we use it is when we adding/removing clients from the list and we need to do it safe anyway
 	MainServer = &Server{ln, make(map[ID]*Client), make(chan Job, 1000), NewIDGenerator(100000, false)}
 	go MainServer.Run()
@@ -103,7 +107,7 @@ func StartServer() {
 	for {
 		conn, err := ln.AcceptTCP()
 		if err != nil {
-			log.Println(err)
+			logNetwork.Error("Accept failed", "err", err)
 			break
 		}
 		MainServer.Jobs <- func() {
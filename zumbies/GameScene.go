@@ -1,7 +1,6 @@
 package zumbies
 
 import (
-	"fmt"
 	"github.com/vova616/garageEngine/engine"
 	"github.com/vova616/garageEngine/engine/components"
 	_ "image/jpeg"
@@ -37,7 +36,7 @@ var (
 
 func CheckError(err error) bool {
 	if err != nil {
-		fmt.Println(err)
+		engine.LogAssets.Error("Loading failed", "err", err)
 		return true
 	}
 	return false
@@ -84,12 +83,12 @@ func (s *GameScene) Load() {
 
 	Layers = make([]*Map, 0, 10)
 
-	ArialFont, err := engine.NewFont("./data/Fonts/arial.ttf", 48)
+	ArialFont, err := engine.Assets.Font("./data/Fonts/arial.ttf", 48)
 	if err != nil {
 		panic(err)
 	}
 
-	ArialFont2, err := engine.NewFont("./data/Fonts/arial.ttf", 24)
+	ArialFont2, err := engine.Assets.Font("./data/Fonts/arial.ttf", 24)
 	if err != nil {
 		panic(err)
 	}
@@ -264,7 +263,7 @@ func (s *GameScene) Load() {
 
 	//s.AddGameObject(shadowShader)
 
-	fmt.Println("Scene loaded")
+	engine.LogScene.Info("Scene loaded", "scene", s.Name())
 }
 
 func (s *GameScene) New() engine.Scene {
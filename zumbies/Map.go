@@ -86,21 +86,14 @@ const (
 )
 
 func test() {
-	println(SideLeft)
-	println(SideRight)
-	println(SideUp)
-	println(SideDown)
-	println(SideReset)
-	println(Tile.SetSide(1, SideDown))
-	println(Tile.SetSide(1, SideDown).Side())
-	println(Tile.SetSide(1, SideDown).SetSide(SideLeft))
-	println(Tile.SetSide(255, SideDown).Type())
-
-	println(Tile.SetType2(255, 15).Type())
-	println(Tile.SetType2(12, 255).Type2())
-	println(Tile(4).SetSide2(SideRight).SetType2(6).Side2() == SideRight, SideRight, SideRight2)
-
-	println((SideRight << 2) == SideRight2)
+	engine.LogEngine.Debug("Tile sides", "left", SideLeft, "right", SideRight, "up", SideUp, "down", SideDown, "reset", SideReset)
+	engine.LogEngine.Debug("Tile.SetSide", "tile", Tile.SetSide(1, SideDown), "side", Tile.SetSide(1, SideDown).Side(),
+		"changed", Tile.SetSide(1, SideDown).SetSide(SideLeft), "type", Tile.SetSide(255, SideDown).Type())
+
+	engine.LogEngine.Debug("Tile.SetType2", "type", Tile.SetType2(255, 15).Type(), "type2", Tile.SetType2(12, 255).Type2())
+	engine.LogEngine.Debug("Tile.SetSide2", "ok", Tile(4).SetSide2(SideRight).SetType2(6).Side2() == SideRight, "right", SideRight, "right2", SideRight2)
+
+	engine.LogEngine.Debug("Tile sides shift", "ok", (SideRight<<2) == SideRight2)
 }
 
 func (t Tile) SetSide(side Tile) Tile {
@@ -405,7 +398,7 @@ func (m *Map) GenerateCollision() {
 	centerx := vect.Float(m.TileSize * float32(m.Width) / 2)
 	centery := vect.Float(m.TileSize * float32(m.Height) / 2)
 
-	println("Map layer", m.Layer)
+	engine.LogPhysics.Debug("Map collision", "layer", m.Layer)
 	for y, xarr := range tilesy {
 		minx := xarr[0]
 		maxx := xarr[0]
@@ -421,7 +414,7 @@ func (m *Map) GenerateCollision() {
 						vect.Vect{vect.Float(float32(minx)*m.TileSize) - centerx, -vect.Float(float32(y)*m.TileSize) + centery},
 						vect.Vect{vect.Float(float32(maxx)*m.TileSize) - centerx, -vect.Float(float32(y)*m.TileSize) + centery},
 						1))
-					println(y, minx, maxx)
+					engine.LogPhysics.Debug("Map segment", "y", y, "minx", minx, "maxx", maxx)
 				}
 				minx = x
 				maxx = x
@@ -432,7 +425,7 @@ func (m *Map) GenerateCollision() {
 				vect.Vect{vect.Float(float32(minx)*m.TileSize) - centerx, -vect.Float(float32(y)*m.TileSize) + centery},
 				vect.Vect{vect.Float(float32(maxx)*m.TileSize) - centerx, -vect.Float(float32(y)*m.TileSize) + centery},
 				1))
-			println(y, minx, maxx)
+			engine.LogPhysics.Debug("Map segment", "y", y, "minx", minx, "maxx", maxx)
 		}
 
 	}
@@ -441,7 +434,7 @@ func (m *Map) GenerateCollision() {
 		miny := yarr[0]
 		maxy := yarr[0]
 		sort.Ints(yarr)
-		println(len(yarr))
+		engine.LogPhysics.Debug("Map column", "x", x, "tiles", len(yarr))
 		for i := 1; i < len(yarr); i++ {
 			y := yarr[i]
 			if maxy+1 == y {
@@ -452,7 +445,7 @@ func (m *Map) GenerateCollision() {
 						vect.Vect{vect.Float(float32(x)*m.TileSize) - centerx, -vect.Float(float32(miny)*m.TileSize) + centery},
 						vect.Vect{vect.Float(float32(x)*m.TileSize) - centerx, -vect.Float(float32(maxy)*m.TileSize) + centery},
 						1))
-					println(x, miny, maxy)
+					engine.LogPhysics.Debug("Map segment", "x", x, "miny", miny, "maxy", maxy)
 				}
 				miny = y
 				maxy = y
@@ -463,14 +456,14 @@ func (m *Map) GenerateCollision() {
 				vect.Vect{vect.Float(float32(x)*m.TileSize) - centerx, -vect.Float(float32(miny)*m.TileSize) + centery},
 				vect.Vect{vect.Float(float32(x)*m.TileSize) - centerx, -vect.Float(float32(maxy)*m.TileSize) + centery},
 				1))
-			println(x, miny, maxy)
+			engine.LogPhysics.Debug("Map segment", "x", x, "miny", miny, "maxy", maxy)
 		}
 
 	}
 
 	m.GameObject().AddComponent(engine.NewPhysicsShapes(true, shapes))
 
-	println("Map end")
+	engine.LogPhysics.Debug("Map collision done", "layer", m.Layer, "shapes", len(shapes))
 }
 
 func (m *Map) Draw() {